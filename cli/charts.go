@@ -0,0 +1,625 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+)
+
+// ChartScope selects which totals of a version a series is read from
+type ChartScope string
+
+const (
+	ChartScopeAll         ChartScope = ""
+	ChartScopeResources   ChartScope = "resources"
+	ChartScopeDataSources ChartScope = "data-sources"
+)
+
+// ChartAxis is what trend charts are plotted against
+type ChartAxis string
+
+const (
+	ChartAxisTime    ChartAxis = "time"    // the version dates so the gaps between releases show
+	ChartAxisVersion ChartAxis = "version" // evenly spaced versions
+)
+
+// ChartSeries is a line on a trend chart, its value for a version is the sum of Metrics less Subtract in Scope
+type ChartSeries struct {
+	Name     string // legend
+	Column   string // csv header
+	Metrics  []string
+	Subtract []string // ie untyped is resources less typed
+	Scope    ChartScope
+
+	Stack   string  // series with the same stack are drawn on top of each other
+	Opacity float32 // of the area under the line, 0 for none
+	CSVOnly bool    // only written to the csv
+}
+
+// ChartSpec describes a trend chart over versions, it is rendered to <name>.html and <name>.csv
+type ChartSpec struct {
+	Name  string // file name and how --charts selects it
+	Title string
+
+	// text/template executed with a chartSummary of the latest version, ie
+	//   {{.Totals.MigrationDone}} done as of {{.Version}}
+	Subtitle string
+
+	Colors []string
+	Series []ChartSeries
+
+	// add a series for every metric recorded, so detectors from the config are included without a spec of their own
+	AllMetrics bool
+
+	// project when series reach zero, drawn dashed past the latest version
+	Projections []ChartProjection
+
+	// renders charts that are not a trend of totals, ie per service. Series, Subtitle and Colors are not used
+	Render func(spec ChartSpec, versions []provider.Version, axis ChartAxis, outPath string) error
+}
+
+// ChartProjection is a burndown of the sum of Metrics in Scope, see provider.ProjectBurndown
+type ChartProjection struct {
+	Name    string // legend
+	Metrics []string
+	Scope   ChartScope
+}
+
+// chartSummary is what subtitle templates are executed with
+type chartSummary struct {
+	Version     string
+	Totals      provider.Totals
+	Resources   provider.Totals
+	DataSources provider.Totals
+	Projections []provider.Projection // in the order of the spec's
+}
+
+var chartTemplateFuncs = template.FuncMap{
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"percent": func(n, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.0f%%", float64(n)/float64(total)*100)
+	},
+	"date": projectedDate,
+}
+
+// chartDate is how dates are written to the csvs and time axes
+func chartDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// chartPoint is the value of a version on the axis
+func chartPoint(axis ChartAxis, v provider.Version, n int) opts.LineData {
+	if axis == ChartAxisTime {
+		return opts.LineData{Name: v.Name, Value: []interface{}{chartDate(v.Date), n}}
+	}
+	return opts.LineData{Value: n}
+}
+
+func chartXAxis(axis ChartAxis) opts.XAxis {
+	if axis == ChartAxisTime {
+		return opts.XAxis{Name: "Date", Type: "time"}
+	}
+	return opts.XAxis{Name: "Version"}
+}
+
+// validateAxis checks the axis is one graphs can plot against
+func validateAxis(axis ChartAxis) error {
+	switch axis {
+	case ChartAxisTime, ChartAxisVersion:
+		return nil
+	}
+
+	return fmt.Errorf("unknown axis '%s', expected time or version", axis)
+}
+
+// projectedDate formats a projected date, they are zero when it is never reached
+func projectedDate(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
+
+// Charts are rendered in order by graphs, --charts selects a subset by name
+var Charts = []ChartSpec{
+	{
+		Name:   "resources-data-sources",
+		Title:  "Resources and Data Sources",
+		Colors: []string{"#2E4555", "#62A0A8", "#C13530"},
+		Series: []ChartSeries{
+			{Name: "Services", Column: "services", Metrics: []string{provider.MetricServices}, CSVOnly: true},
+			{Name: "Resources", Column: "resources", Metrics: []string{provider.MetricResources}, Stack: "elements", Opacity: 0.7},
+			{Name: "Data Sources", Column: "data-sources", Metrics: []string{provider.MetricDataSources}, Stack: "elements", Opacity: 0.7},
+		},
+	},
+	{
+		Name:     "pandora-sdk-migration",
+		Title:    "Pandora SDK Migration",
+		Subtitle: "{{.Totals.MigrationDone}}/{{add .Totals.Resources .Totals.DataSources}} ({{percent .Totals.MigrationDone (add .Totals.Resources .Totals.DataSources)}}) done as of {{.Version}}",
+		Colors:   []string{"#000000", "#2E4555", "#62A0A8"},
+		Series: []ChartSeries{
+			{Name: "Services", Column: "services", Metrics: []string{provider.MetricServices}, CSVOnly: true},
+			{Name: "Resources", Column: "resources", Metrics: []string{provider.MetricResources}, CSVOnly: true},
+			{Name: "Data Sources", Column: "data-sources", Metrics: []string{provider.MetricDataSources}, CSVOnly: true},
+			{Name: "Total Resources/DataSources", Column: "total", Metrics: []string{provider.MetricResources, provider.MetricDataSources}, Opacity: 0.001},
+			{Name: "Resources Migrated", Column: "resources-pandora", Metrics: []string{provider.MigrationDone.Metric()}, Scope: ChartScopeResources, Stack: "elements", Opacity: 1.0},
+			{Name: "Data Sources Migrated", Column: "data-sources-pandora", Metrics: []string{provider.MigrationDone.Metric()}, Scope: ChartScopeDataSources, Stack: "elements", Opacity: 1.0},
+		},
+	},
+	{
+		Name:  "pandora-sdk-migration-burndown",
+		Title: "Pandora SDK Migration",
+		Subtitle: "{{.Totals.MigrationRemaining}}/{{add .Totals.Resources .Totals.DataSources}} ({{percent .Totals.MigrationRemaining (add .Totals.Resources .Totals.DataSources)}}) still to migrate and {{.Totals.SdkTrack1}} using track1 as of {{.Version}}" +
+			"{{with index .Projections 0}}{{if .Done}}, track1 gone{{else if .Projected}}, track1 projected gone {{date .Recent}} ({{date .Earliest}} to {{date .Latest}}){{end}}{{end}}" +
+			"{{with index .Projections 1}}{{if .Done}}, migration done{{else if .Projected}}, migration projected done {{date .Recent}} ({{date .Earliest}} to {{date .Latest}}){{end}}{{end}}",
+		Colors: []string{"#000000", "#2E4555", "#62A0A8", "#C13530", "#E98B2A", "#6E7074", "#546570", "#C13530", "#2E4555"},
+		Series: []ChartSeries{
+			{Name: "Services", Column: "services", Metrics: []string{provider.MetricServices}, CSVOnly: true},
+			{Name: "Resources", Column: "resources", Metrics: []string{provider.MetricResources}, CSVOnly: true},
+			{Name: "Data Sources", Column: "data-sources", Metrics: []string{provider.MetricDataSources}, CSVOnly: true},
+			{Name: "Total Resources/DataSources", Column: "total", Metrics: []string{provider.MetricResources, provider.MetricDataSources}, Opacity: 0.001},
+			{Name: "Resources Remaining", Column: "resources-pandora-left", Metrics: []string{provider.MigrationNotStarted.Metric(), provider.MigrationPartial.Metric()}, Scope: ChartScopeResources, Stack: "elements", Opacity: 1.0},
+			{Name: "Data Sources Remaining", Column: "data-sources-pandora-left", Metrics: []string{provider.MigrationNotStarted.Metric(), provider.MigrationPartial.Metric()}, Scope: ChartScopeDataSources, Stack: "elements", Opacity: 1.0},
+			// track2 is part of what remains so it is stacked separately over the top
+			{Name: "Resources Using Track2", Column: "resources-track2", Metrics: []string{provider.MetricSdkTrack2}, Scope: ChartScopeResources, Stack: "track2", Opacity: 0.5},
+			{Name: "Data Sources Using Track2", Column: "data-sources-track2", Metrics: []string{provider.MetricSdkTrack2}, Scope: ChartScopeDataSources, Stack: "track2", Opacity: 0.5},
+			{Name: "Resources Using Track1", Column: "resources-track1", Metrics: []string{provider.MetricSdkTrack1}, Scope: ChartScopeResources, Stack: "track1", Opacity: 0.5},
+			{Name: "Data Sources Using Track1", Column: "data-sources-track1", Metrics: []string{provider.MetricSdkTrack1}, Scope: ChartScopeDataSources, Stack: "track1", Opacity: 0.5},
+		},
+		// the same burndowns as the report prints
+		Projections: []ChartProjection{
+			{
+				Name:    "Projected Track1",
+				Metrics: []string{provider.MetricSdkTrack1},
+			},
+			{
+				Name:    "Projected Remaining",
+				Metrics: []string{provider.MigrationNotStarted.Metric(), provider.MigrationPartial.Metric()},
+			},
+		},
+	},
+	{
+		Name:     "typed-resources",
+		Title:    "Typed Resources",
+		Subtitle: "{{.Resources.Typed}}/{{.Resources.Resources}} ({{percent .Resources.Typed .Resources.Resources}}) typed as of {{.Version}}",
+		Colors:   []string{"#2E4555", "#C13530"},
+		Series: []ChartSeries{
+			{Name: "Resources", Column: "resources", Metrics: []string{provider.MetricResources}, CSVOnly: true},
+			{Name: "Typed", Column: "typed", Metrics: []string{provider.MetricTyped}, Scope: ChartScopeResources, Stack: "elements", Opacity: 1.0},
+			{Name: "Untyped", Column: "untyped", Metrics: []string{provider.MetricResources}, Subtract: []string{provider.MetricTyped}, Scope: ChartScopeResources, Stack: "elements", Opacity: 0.7},
+		},
+	},
+	{
+		Name:     "typed-data-sources",
+		Title:    "Typed Data Sources",
+		Subtitle: "{{.DataSources.Typed}}/{{.DataSources.DataSources}} ({{percent .DataSources.Typed .DataSources.DataSources}}) typed as of {{.Version}}",
+		Colors:   []string{"#62A0A8", "#E98B2A"},
+		Series: []ChartSeries{
+			{Name: "Data Sources", Column: "data-sources", Metrics: []string{provider.MetricDataSources}, CSVOnly: true},
+			{Name: "Typed", Column: "typed", Metrics: []string{provider.MetricTyped}, Scope: ChartScopeDataSources, Stack: "elements", Opacity: 1.0},
+			{Name: "Untyped", Column: "untyped", Metrics: []string{provider.MetricDataSources}, Subtract: []string{provider.MetricTyped}, Scope: ChartScopeDataSources, Stack: "elements", Opacity: 0.7},
+		},
+	},
+	{
+		Name:       "metrics",
+		Title:      "Metrics",
+		AllMetrics: true,
+	},
+	{
+		Name:   "service-migration-heatmap",
+		Title:  "Pandora SDK Migration by Service",
+		Render: RenderServiceMigrationHeatmap,
+	},
+	{
+		Name:   "service-burndowns",
+		Title:  "Pandora SDK Migration Burndown by Service",
+		Render: RenderServiceBurndowns,
+	},
+}
+
+// ChartNames returns the names of every chart in order
+func ChartNames() []string {
+	names := make([]string, 0, len(Charts))
+	for _, s := range Charts {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// SelectCharts returns the charts called names in the order given, or all of them when there are none
+func SelectCharts(names []string) ([]ChartSpec, error) {
+	if len(names) == 0 {
+		return Charts, nil
+	}
+
+	specs := []ChartSpec{}
+	for _, name := range names {
+		found := false
+		for _, s := range Charts {
+			if s.Name == name {
+				specs = append(specs, s)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown chart '%s', expected one of %s", name, strings.Join(ChartNames(), ", "))
+		}
+	}
+
+	return specs, nil
+}
+
+// chartTotals are the totals of a version for every scope
+type chartTotals map[ChartScope]provider.Totals
+
+func newChartTotals(v provider.Version) chartTotals {
+	return chartTotals{
+		ChartScopeAll:         v.CalculateTotals(),
+		ChartScopeResources:   v.CalculateResourceTotals(),
+		ChartScopeDataSources: v.CalculateDataSourceTotals(),
+	}
+}
+
+func (s ChartSeries) value(t chartTotals) int {
+	n := 0
+	for _, m := range s.Metrics {
+		n += t[s.Scope][m]
+	}
+	for _, m := range s.Subtract {
+		n -= t[s.Scope][m]
+	}
+	return n
+}
+
+// seriesFor returns the spec's series with one for each recorded metric when AllMetrics is set
+func (spec ChartSpec) seriesFor(totals []chartTotals) []ChartSeries {
+	if !spec.AllMetrics {
+		return spec.Series
+	}
+
+	all := provider.Totals{}
+	for _, t := range totals {
+		all = all.Add(t[ChartScopeAll])
+	}
+
+	series := append([]ChartSeries{}, spec.Series...)
+	for _, m := range all.Metrics() {
+		series = append(series, ChartSeries{Name: m, Column: m, Metrics: []string{m}})
+	}
+	return series
+}
+
+// project fits each of the spec's projections to versions
+func (spec ChartSpec) project(versions []provider.Version, totals []chartTotals) []provider.Projection {
+	projections := make([]provider.Projection, 0, len(spec.Projections))
+	for _, cp := range spec.Projections {
+		s := ChartSeries{Metrics: cp.Metrics, Scope: cp.Scope}
+		points := make([]provider.BurndownPoint, 0, len(versions))
+		for i, v := range versions {
+			points = append(points, provider.BurndownPoint{Date: v.Date, Remaining: s.value(totals[i])})
+		}
+
+		projections = append(projections, provider.ProjectBurndown(points))
+	}
+
+	return projections
+}
+
+func (spec ChartSpec) subtitle(v provider.Version, t chartTotals, p []provider.Projection) (string, error) {
+	if spec.Subtitle == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(spec.Name).Funcs(chartTemplateFuncs).Parse(spec.Subtitle)
+	if err != nil {
+		return "", fmt.Errorf("parsing subtitle: %w", err)
+	}
+
+	sb := strings.Builder{}
+	err = tmpl.Execute(&sb, chartSummary{
+		Version:     v.Name,
+		Totals:      t[ChartScopeAll],
+		Resources:   t[ChartScopeResources],
+		DataSources: t[ChartScopeDataSources],
+		Projections: p,
+	})
+	if err != nil {
+		return "", fmt.Errorf("executing subtitle: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// RenderChart writes the spec's csv and html for versions, which are expected oldest first
+func RenderChart(spec ChartSpec, versions []provider.Version, axis ChartAxis, outPath string) error {
+	if spec.Render != nil {
+		return spec.Render(spec, versions, axis, outPath)
+	}
+
+	totals := make([]chartTotals, 0, len(versions))
+	for _, v := range versions {
+		totals = append(totals, newChartTotals(v))
+	}
+	series := spec.seriesFor(totals)
+
+	var xAxis []string
+	lines := make([][]opts.LineData, len(series))
+
+	header := []string{"version", "date"}
+	for _, s := range series {
+		header = append(header, s.Column)
+	}
+
+	data := [][]string{header}
+	for i, v := range versions {
+		xAxis = append(xAxis, v.Name)
+
+		row := []string{v.Name, chartDate(v.Date)}
+		for j, s := range series {
+			n := s.value(totals[i])
+			lines[j] = append(lines[j], chartPoint(axis, v, n))
+			row = append(row, strconv.Itoa(n))
+		}
+		data = append(data, row)
+	}
+
+	// write raw data
+	if err := writeChartCSV(outPath, spec.Name, data); err != nil {
+		return err
+	}
+
+	projections := spec.project(versions, totals)
+
+	subtitle := ""
+	if len(versions) > 0 {
+		var err error
+		subtitle, err = spec.subtitle(versions[len(versions)-1], totals[len(totals)-1], projections)
+		if err != nil {
+			return fmt.Errorf("chart %s: %w", spec.Name, err)
+		}
+	}
+
+	// render graph
+	graph := charts.NewLine()
+	graph.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    spec.Title,
+			Subtitle: subtitle,
+			Left:     "center"}), // nolint:misspell
+
+		charts.WithXAxisOpts(chartXAxis(axis)),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Total",
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1500px",
+			Height: "750px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:      true,
+			Trigger:   "axis",
+			TriggerOn: "mousemove",
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{Show: true}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+			Top:  "bottom",
+			Left: "center", // nolint:misspell
+		}),
+	)
+	if len(spec.Colors) > 0 {
+		graph.SetGlobalOptions(charts.WithColorsOpts(opts.Colors(spec.Colors)))
+	}
+
+	// a time axis takes its values from the points
+	if axis == ChartAxisVersion {
+		graph.SetXAxis(xAxis)
+	}
+	for i, s := range series {
+		if s.CSVOnly {
+			continue
+		}
+
+		seriesOpts := []charts.SeriesOpts{}
+		if s.Opacity > 0 {
+			seriesOpts = append(seriesOpts, charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: s.Opacity}))
+		}
+		if s.Stack != "" {
+			seriesOpts = append(seriesOpts, charts.WithLineChartOpts(opts.LineChart{Stack: s.Stack}))
+		}
+
+		graph.AddSeries(s.Name, lines[i], seriesOpts...)
+	}
+
+	addProjections(graph, spec, projections, axis, xAxis)
+
+	return writeChartHTML(outPath, spec.Name, graph)
+}
+
+// addProjections continues each projection from the latest version to where it reaches zero, on a time axis with the
+// band either side of it. on a version axis each projected date gets a category of its own after the versions
+func addProjections(graph *charts.Line, spec ChartSpec, projections []provider.Projection, axis ChartAxis, xAxis []string) {
+	dashed := charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed"})
+
+	if axis == ChartAxisTime {
+		for i, p := range projections {
+			if p.Recent.IsZero() || p.Done {
+				continue
+			}
+
+			name := spec.Projections[i].Name
+			from := opts.LineData{Value: []interface{}{chartDate(p.From.Date), p.From.Remaining}}
+
+			graph.AddSeries(name, []opts.LineData{from, {Name: "projected", Value: []interface{}{chartDate(p.Recent), 0}}}, dashed)
+			graph.AddSeries(name+" (earliest)", []opts.LineData{from, {Name: "earliest", Value: []interface{}{chartDate(p.Earliest), 0}}}, dashed)
+			if !p.Latest.IsZero() {
+				graph.AddSeries(name+" (latest)", []opts.LineData{from, {Name: "latest", Value: []interface{}{chartDate(p.Latest), 0}}}, dashed)
+			}
+		}
+		return
+	}
+
+	// the projected categories have to be in date order
+	projected := []int{}
+	for i, p := range projections {
+		if !p.Recent.IsZero() && !p.Done {
+			projected = append(projected, i)
+		}
+	}
+	if len(projected) == 0 || len(xAxis) == 0 {
+		return
+	}
+	sort.SliceStable(projected, func(a, b int) bool {
+		return projections[projected[a]].Recent.Before(projections[projected[b]].Recent)
+	})
+
+	categories := append([]string{}, xAxis...)
+	for _, i := range projected {
+		categories = append(categories, projectedDate(projections[i].Recent)+" (projected)")
+	}
+	graph.SetXAxis(categories)
+
+	for n, i := range projected {
+		line := make([]opts.LineData, 0, len(categories))
+		for range xAxis[1:] {
+			line = append(line, opts.LineData{Value: "-"})
+		}
+		line = append(line, opts.LineData{Value: projections[i].From.Remaining})
+		for m := range projected {
+			if m == n {
+				line = append(line, opts.LineData{Value: 0})
+			} else {
+				line = append(line, opts.LineData{Value: "-"})
+			}
+		}
+
+		graph.AddSeries(spec.Projections[i].Name, line, dashed, charts.WithLineChartOpts(opts.LineChart{ConnectNulls: true}))
+	}
+}
+
+// chartRenderer is a go-echarts chart or page
+type chartRenderer interface {
+	Render(w io.Writer) error
+}
+
+// writeChartCSV writes a chart's raw data to outPath/name.csv
+func writeChartCSV(outPath, name string, data [][]string) error {
+	file, err := os.Create(outPath + "/" + name + ".csv")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	// WriteAll flushes and returns any error from doing so
+	w := csv.NewWriter(file)
+	if err := w.WriteAll(data); err != nil {
+		return fmt.Errorf("writing %s.csv: %w", name, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing %s.csv: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeChartHTML renders a chart or page to outPath/name.html
+func writeChartHTML(outPath, name string, chart chartRenderer) error {
+	file, err := os.Create(outPath + "/" + name + ".html")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := chart.Render(file); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing %s.html: %w", name, err)
+	}
+
+	return nil
+}
+
+// Histogram is a bar chart of how many of something fall in each bucket, a distribution at a single version rather
+// then a trend over versions
+type Histogram struct {
+	Name     string // file name of the csv and html
+	Title    string
+	Subtitle string
+	Color    string
+
+	BucketAxis string // name of the bucket axis and its csv column
+	CountAxis  string // name of the count axis
+	Column     string // csv column of the counts
+
+	Buckets []string
+	Counts  []int
+}
+
+// RenderHistogram writes the histogram's csv and html
+func RenderHistogram(h Histogram, outPath string) error {
+	data := [][]string{{strings.ToLower(h.BucketAxis), h.Column}}
+	bars := make([]opts.BarData, 0, len(h.Counts))
+	for i, n := range h.Counts {
+		data = append(data, []string{h.Buckets[i], strconv.Itoa(n)})
+		bars = append(bars, opts.BarData{Value: n})
+	}
+
+	// write raw data
+	if err := writeChartCSV(outPath, h.Name, data); err != nil {
+		return err
+	}
+
+	// render graph
+	graph := charts.NewBar()
+	graph.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    h.Title,
+			Subtitle: h.Subtitle,
+			Left:     "center"}), // nolint:misspell
+
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: h.BucketAxis,
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: h.CountAxis,
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1500px",
+			Height: "750px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithColorsOpts(opts.Colors{h.Color}),
+		charts.WithToolboxOpts(opts.Toolbox{Show: true}),
+	)
+
+	graph.SetXAxis(h.Buckets).
+		AddSeries(h.CountAxis, bars)
+
+	return writeChartHTML(outPath, h.Name, graph)
+}
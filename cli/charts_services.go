@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+)
+
+// serviceTotals are the totals of every service in every version, services are matched by name
+type serviceTotals struct {
+	services []string                     // sorted names of every service in any version
+	totals   []map[string]provider.Totals // per version, keyed by service name
+}
+
+func newServiceTotals(versions []provider.Version) serviceTotals {
+	st := serviceTotals{}
+
+	seen := map[string]bool{}
+	for _, v := range versions {
+		totals := map[string]provider.Totals{}
+		for _, s := range v.Services {
+			totals[s.Name] = s.CalculateTotals()
+
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				st.services = append(st.services, s.Name)
+			}
+		}
+		st.totals = append(st.totals, totals)
+	}
+	sort.Strings(st.services)
+
+	return st
+}
+
+// serviceLabel is how a service is named on charts, the flat azurerm package is marked so it isn't taken for a service
+func serviceLabel(name string) string {
+	if name == provider.FlatServiceName {
+		return name + " (flat package)"
+	}
+
+	return name
+}
+
+// migrated returns the percentage of the service's elements migrated to pandora, false when it doesn't exist in
+// the version or has nothing to migrate
+func (st serviceTotals) migrated(version int, service string) (float64, bool) {
+	t, ok := st.totals[version][service]
+	if !ok || t.MigrationApplicable() == 0 {
+		return 0, false
+	}
+
+	return float64(t.MigrationDone()) / float64(t.MigrationApplicable()) * 100, true
+}
+
+// RenderServiceMigrationHeatmap charts each service's pandora migration percentage for every version
+// a heatmap's cells are always one per version, so axis only changes the column headers to dates
+func RenderServiceMigrationHeatmap(spec ChartSpec, versions []provider.Version, axis ChartAxis, outPath string) error {
+	st := newServiceTotals(versions)
+
+	var xAxis []string
+	for _, v := range versions {
+		if axis == ChartAxisTime {
+			xAxis = append(xAxis, projectedDate(v.Date)+" "+v.Name)
+		} else {
+			xAxis = append(xAxis, v.Name)
+		}
+	}
+
+	var cells []opts.HeatMapData
+	var labels []string
+	data := [][]string{append([]string{"service"}, xAxis...)}
+	for y, s := range st.services {
+		labels = append(labels, serviceLabel(s))
+
+		row := []string{serviceLabel(s)}
+		for x := range versions {
+			p, ok := st.migrated(x, s)
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+
+			// rounded once so the cells and csv agree
+			n := int(math.Round(p))
+			cells = append(cells, opts.HeatMapData{Value: [3]interface{}{x, y, n}})
+			row = append(row, strconv.Itoa(n))
+		}
+		data = append(data, row)
+	}
+
+	// write raw data
+	if err := writeChartCSV(outPath, spec.Name, data); err != nil {
+		return err
+	}
+
+	// a row per service so the page grows with them
+	height := 20*len(st.services) + 200
+	if height < 750 {
+		height = 750
+	}
+
+	// render graph
+	graph := charts.NewHeatMap()
+	graph.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    spec.Title,
+			Subtitle: "percentage of each service's resources and data sources migrated, blank when there is nothing to migrate",
+			Left:     "center"}), // nolint:misspell
+
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: chartXAxis(axis).Name,
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Service",
+			Type: "category",
+			Data: labels,
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1500px",
+			Height: fmt.Sprintf("%dpx", height),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show: true,
+		}),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: true,
+			Min:        0,
+			Max:        100,
+			Text:       []string{"100%", "0%"},
+			InRange: &opts.VisualMapInRange{
+				Color: []string{"#C13530", "#E98B2A", "#62A0A8", "#2E4555"},
+			},
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{Show: true}),
+	)
+
+	graph.SetXAxis(xAxis).
+		AddSeries("Migrated", cells)
+
+	return writeChartHTML(outPath, spec.Name, graph)
+}
+
+// RenderServiceBurndowns renders a small burndown for every service that has had anything to migrate onto one page
+func RenderServiceBurndowns(spec ChartSpec, versions []provider.Version, axis ChartAxis, outPath string) error {
+	st := newServiceTotals(versions)
+
+	var xAxis []string
+	for _, v := range versions {
+		xAxis = append(xAxis, v.Name)
+	}
+
+	page := components.NewPage()
+	page.PageTitle = spec.Title
+	page.SetLayout(components.PageFlexLayout)
+
+	data := [][]string{{"service", "version", "date", "total", "remaining"}}
+	for _, s := range st.services {
+		var total, remaining []opts.LineData
+		applicable := false
+		for i, v := range versions {
+			t := st.totals[i][s] // nil when the service doesn't exist in the version, which reads as 0
+
+			if t.MigrationApplicable() > 0 {
+				applicable = true
+			}
+
+			total = append(total, chartPoint(axis, v, t.Resources()+t.DataSources()))
+			remaining = append(remaining, chartPoint(axis, v, t.MigrationRemaining()))
+
+			data = append(data, []string{serviceLabel(s), v.Name, chartDate(v.Date),
+				strconv.Itoa(t.Resources() + t.DataSources()),
+				strconv.Itoa(t.MigrationRemaining()),
+			})
+		}
+
+		if !applicable {
+			continue
+		}
+
+		graph := charts.NewLine()
+		graph.SetGlobalOptions(
+			charts.WithTitleOpts(opts.Title{
+				Title: serviceLabel(s),
+			}),
+			charts.WithInitializationOpts(opts.Initialization{
+				Width:  "500px",
+				Height: "300px",
+			}),
+			charts.WithTooltipOpts(opts.Tooltip{
+				Show:    true,
+				Trigger: "axis",
+			}),
+			charts.WithXAxisOpts(chartXAxis(axis)),
+			charts.WithColorsOpts(opts.Colors{"#000000", "#C13530"}),
+		)
+
+		if axis == ChartAxisVersion {
+			graph.SetXAxis(xAxis)
+		}
+		graph.AddSeries("Total", total,
+			charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: 0.001})).
+			AddSeries("Remaining", remaining,
+				charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: 1.0}))
+
+		page.AddCharts(graph)
+	}
+
+	// write raw data
+	if err := writeChartCSV(outPath, spec.Name, data); err != nil {
+		return err
+	}
+
+	return writeChartHTML(outPath, spec.Name, page)
+}
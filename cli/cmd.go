@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/katbyte/gogo-azurerm-info/version"
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -53,7 +52,7 @@ func Make(cmdName string) (*cobra.Command, error) {
 	// lint? probably not
 
 	root.AddCommand(&cobra.Command{
-		Use:           "report [repo path] [pandora-sdk-issue]",
+		Use:           "report [repo path] [pandora-sdk-issue|schema|api-versions|api-age]",
 		Short:         cmdName + " calculates a report for the provider (services, resources, datasources, sdk in use etc)",
 		Args:          cobra.RangeArgs(1, 2),
 		SilenceErrors: true,
@@ -62,7 +61,7 @@ func Make(cmdName string) (*cobra.Command, error) {
 	})
 
 	root.AddCommand(&cobra.Command{
-		Use:           "list [repo path] [track1|typed|create-update]",
+		Use:           "list [repo path] [track1|track2|typed|create-update|built-in-parse|unregistered|tests|clients|excluded|<detector>]",
 		Short:         cmdName + " list resources that need migration",
 		Args:          cobra.ExactArgs(2),
 		SilenceErrors: true,
@@ -71,12 +70,25 @@ func Make(cmdName string) (*cobra.Command, error) {
 	})
 
 	root.AddCommand(&cobra.Command{
-		Use:           "graphs",
-		Args:          cobra.MaximumNArgs(2),
+		Use:           "breaking [repo path] [from tag] [to tag]",
+		Short:         cmdName + " reports the breaking schema changes between two versions of the provider",
+		Args:          cobra.ExactArgs(3),
 		SilenceErrors: true,
-		RunE:          CmdGraphs,
+		RunE:          CmdBreaking,
 	})
 
+	graphs := &cobra.Command{
+		Use:           "graphs [repo path] [oldest tag]",
+		Short:         cmdName + " charts how the provider has changed over its releases",
+		Args:          cobra.RangeArgs(1, 2),
+		SilenceErrors: true,
+		RunE:          CmdGraphs,
+	}
+	if err := configureGraphsFlags(graphs); err != nil {
+		return nil, fmt.Errorf("unable to configure graphs flags: %w", err)
+	}
+	root.AddCommand(graphs)
+
 	// todo emoji stats/counter
 
 	root.AddCommand(&cobra.Command{
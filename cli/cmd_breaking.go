@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	c "github.com/gookit/color" // nolint:misspell
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+	"github.com/spf13/cobra"
+)
+
+func CmdBreaking(_ *cobra.Command, args []string) error {
+	repoPath := args[0]
+	fromTag := args[1]
+	toTag := args[2]
+
+	f := GetFlags()
+
+	outPath := f.BreakingPath
+	err := os.MkdirAll(outPath, 0755)
+	if err != nil {
+		return fmt.Errorf("making path %s: %w", outPath, err)
+	}
+
+	cfg, err := LoadConfig(f.Config)
+	if err != nil {
+		return err
+	}
+
+	r, err := provider.NewRepo(repoPath)
+	if err != nil {
+		return fmt.Errorf("opening repo: %w", err)
+	}
+
+	from, err := scanTagSchemas(r, fromTag, cfg.Exclusions)
+	if err != nil {
+		return err
+	}
+
+	to, err := scanTagSchemas(r, toTag, cfg.Exclusions)
+	if err != nil {
+		return err
+	}
+
+	bc := provider.CompareSchemas(from, to)
+
+	name := fmt.Sprintf("%s/breaking-changes-%s-%s", outPath, fromTag, toTag)
+	if err := os.WriteFile(name+".md", []byte(BreakingChangesMarkdown(bc)), 0644); err != nil { // nolint:gosec
+		return fmt.Errorf("writing %s.md: %w", name, err)
+	}
+
+	bytes, err := json.MarshalIndent(bc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling breaking changes: %w", err)
+	}
+	if err := os.WriteFile(name+".json", bytes, 0644); err != nil { // nolint:gosec
+		return fmt.Errorf("writing %s.json: %w", name, err)
+	}
+
+	if len(bc.Changes) == 0 {
+		c.Printf("<green>no breaking changes</> between <green>%s</> and <green>%s</>\n", fromTag, toTag)
+	} else {
+		c.Printf("<red>%d</> breaking changes between <green>%s</> and <green>%s</>\n", len(bc.Changes), fromTag, toTag)
+	}
+	c.Printf("  written to <cyan>%s.md</> and <cyan>%s.json</>\n", name, name)
+
+	return nil
+}
+
+func scanTagSchemas(r *provider.Repo, tag string, exclusions provider.Exclusions) (*provider.Version, error) {
+	c.Printf("  reading <green>%s</>...", tag)
+	files, err := r.TagFS(tag)
+	if err != nil {
+		return nil, fmt.Errorf("reading tag: %w", err)
+	}
+
+	v := provider.Version{
+		Name:        tag,
+		Path:        r.Path,
+		FS:          files,
+		ScanSchemas: true,
+		Workers:     GetFlags().Workers,
+		Exclusions:  exclusions,
+
+		ServicesPath: GetFlags().ServicesPath,
+	}
+
+	if err := v.ScanServices(); err != nil {
+		return nil, fmt.Errorf("scanning services for %s: %w", tag, err)
+	}
+
+	t := v.CalculateTotals()
+	c.Printf(" <magenta>%d</> services, <cyan>%d</> resources and <lightBlue>%d</> data sources\n", len(v.Services), t.Resources(), t.DataSources())
+
+	return &v, nil
+}
+
+func BreakingChangesMarkdown(bc provider.BreakingChanges) string {
+	sb := strings.Builder{}
+
+	sb.WriteString(fmt.Sprintf("## Breaking Changes between `%s` and `%s`\n\n", bc.From, bc.To))
+
+	if len(bc.Changes) == 0 {
+		sb.WriteString("No breaking changes found.\n")
+		return sb.String()
+	}
+
+	// removals first
+	removed := false
+	for _, ch := range bc.Changes {
+		switch ch.Kind {
+		case provider.BreakingResourceRemoved:
+			sb.WriteString(fmt.Sprintf("- resource `%s` has been removed\n", ch.Name))
+		case provider.BreakingDataSourceRemoved:
+			sb.WriteString(fmt.Sprintf("- data source `%s` has been removed\n", ch.Name))
+		default:
+			continue
+		}
+		removed = true
+	}
+	if removed {
+		sb.WriteString("\n")
+	}
+
+	// changes are sorted by resources then data sources and name so just start a new section when it changes
+	heading := ""
+	for _, ch := range bc.Changes {
+		if ch.Kind == provider.BreakingResourceRemoved || ch.Kind == provider.BreakingDataSourceRemoved {
+			continue
+		}
+
+		h := "### Resource `" + ch.Name + "`"
+		if ch.DataSource {
+			h = "### Data Source `" + ch.Name + "`"
+		}
+		if h != heading {
+			if heading != "" {
+				sb.WriteString("\n")
+			}
+			heading = h
+			sb.WriteString(h + "\n\n")
+		}
+
+		switch ch.Kind {
+		case provider.BreakingAttributeRemoved:
+			sb.WriteString(fmt.Sprintf("- `%s` has been removed\n", ch.Attribute))
+		case provider.BreakingAttributeRenamed:
+			sb.WriteString(fmt.Sprintf("- `%s` has been removed, possibly renamed to `%s`\n", ch.Attribute, ch.To))
+		case provider.BreakingAttributeNowRequired:
+			sb.WriteString(fmt.Sprintf("- `%s` is now required\n", ch.Attribute))
+		case provider.BreakingAttributeNowForceNew:
+			sb.WriteString(fmt.Sprintf("- `%s` is now ForceNew\n", ch.Attribute))
+		case provider.BreakingAttributeTypeChanged:
+			sb.WriteString(fmt.Sprintf("- `%s` type changed from `%s` to `%s`\n", ch.Attribute, ch.From, ch.To))
+		}
+	}
+
+	return sb.String()
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	c "github.com/gookit/color" // nolint:misspell
@@ -9,34 +11,153 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// listFilters select the resources and data sources for each list type in json output
+var listFilters = map[string]elementFilter{
+	"track1": func(e interface{}) bool {
+		return elementOf(e).SdkAzureSdkGo
+	},
+	"track2": func(e interface{}) bool {
+		return elementOf(e).SdkAzureSdkGoTrack2
+	},
+	"typed": func(e interface{}) bool {
+		return !elementOf(e).IsTyped
+	},
+	"create-update": func(e interface{}) bool {
+		r, ok := e.(provider.Resource)
+		return ok && r.SharedCreateUpdate
+	},
+	"built-in-parse": func(e interface{}) bool {
+		return elementOf(e).UsesBuiltInParse
+	},
+	"unregistered": func(e interface{}) bool {
+		rd := elementOf(e)
+		return !rd.IsRegistered && rd.Service.RegistrationGoFileName != ""
+	},
+	"clients": func(e interface{}) bool {
+		rd := elementOf(e)
+		for _, cl := range rd.Service.LegacyClients() {
+			for _, name := range cl.UsedBy {
+				if name == rd.Name {
+					return true
+				}
+			}
+		}
+		return false
+	},
+	// excluded files are not resources or data sources, they are listed on each service
+	"excluded": func(e interface{}) bool {
+		return false
+	},
+	"tests": func(e interface{}) bool {
+		switch e := e.(type) {
+		case provider.Resource:
+			return len(e.AccTests.MissingStandardResourceTests()) > 0
+		case provider.DataSource:
+			return len(e.AccTests.MissingStandardDataSourceTests()) > 0
+		}
+		return false
+	},
+}
+
+// listNames returns the built in list types sorted
+func listNames() []string {
+	names := make([]string, 0, len(listFilters))
+	for name := range listFilters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 func CmdList(_ *cobra.Command, args []string) error {
 	repoPath := args[0]
+	f := GetFlags()
+
+	if err := f.validateOutput(); err != nil {
+		return err
+	}
 
-	c.Printf("Scanning <cyan>%s</>... ", repoPath)
+	cfg, err := LoadConfig(f.Config)
+	if err != nil {
+		return err
+	}
+
+	// detectors from the config are lists too, their names never clash with the built in ones
+	detector, isDetector := cfg.Detectors.Get(args[1])
+	filter, ok := listFilters[args[1]]
+	if isDetector {
+		filter = func(e interface{}) bool {
+			return elementOf(e).Detected[detector.Name] > 0
+		}
+	} else if !ok {
+		return fmt.Errorf("unknown list type '%s'", args[1])
+	}
+
+	if f.Output != "json" {
+		c.Printf("Scanning <cyan>%s</>... ", repoPath)
+	}
 
 	v := provider.Version{
 		Name: "main",
 		Path: repoPath,
 		Date: time.Time{},
+
+		Detectors:  cfg.Detectors,
+		Exclusions: cfg.Exclusions,
+		Workers:    f.Workers,
+
+		ServicesPath: f.ServicesPath,
 	}
 
-	err := v.ScanServices()
+	err = v.ScanServices()
 	if err != nil {
 		return fmt.Errorf("scanning services: %w", err)
 	}
 
+	if f.Output == "json" {
+		out := NewJSONOutput("list", args[1], v, filter)
+		if args[1] == "built-in-parse" {
+			ids, err := v.ScanResourceIDs()
+			if err != nil {
+				return fmt.Errorf("scanning resource ids: %w", err)
+			}
+			out.Version.AddParsers(ids)
+		}
+
+		return out.Write()
+	}
+
 	t := v.CalculateTotals()
-	c.Printf(" <magenta>%d</> services with <lightGreen>%d</> resources and <lightBlue>%d</> data sources\n", len(v.Services), t.Resources, t.DataSources)
+	c.Printf(" <magenta>%d</> services with <lightGreen>%d</> resources and <lightBlue>%d</> data sources\n", len(v.Services), t.Resources(), t.DataSources())
+
+	defer PrintDiagnostics(v.Diagnostics())
 
 	switch args[1] {
 	case "track1":
 		ListTrack1(v)
+	case "track2":
+		ListTrack2(v)
 	case "typed":
 		ListTyped(v)
 	case "create-update":
 		ListSharedCreateUpdate(v)
 	case "built-in-parse":
-		ListBuiltInParse(v)
+		ids, err := v.ScanResourceIDs()
+		if err != nil {
+			return fmt.Errorf("scanning resource ids: %w", err)
+		}
+		ListBuiltInParse(v, ids)
+	case "unregistered":
+		ListUnregistered(v)
+	case "tests":
+		ListTests(v)
+	case "clients":
+		ListClients(v)
+	case "excluded":
+		ListExcluded(v)
+	case detector.Name:
+		ListDetected(v, detector)
 	default:
 		return fmt.Errorf("unknown list type '%s'", args[1])
 	}
@@ -49,22 +170,22 @@ func ListTrack1(v provider.Version) {
 	toMigrate := 0
 	for _, s := range v.Services {
 		t := s.CalculateTotals()
-		total += t.Resources
-		total += t.DataSources
+		total += t.Resources()
+		total += t.DataSources()
 
-		if t.SdkTrack1 == 0 {
+		if t.SdkTrack1() == 0 {
 			continue
 		}
 
-		toMigrate += t.SdkTrack1
+		toMigrate += t.SdkTrack1()
 
-		c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> using track1)\n", s.Name, t.SdkTrack1, t.Resources+t.DataSources)
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> using track1)\n", s.Name, t.SdkTrack1(), t.Resources()+t.DataSources())
 		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
 			return rds.SdkAzureSdkGo
 		})
 
 		for _, r := range rds {
-			if r.SdkPandora {
+			if r.MigrationState() == provider.MigrationPartial {
 				c.Printf("    <gray>%s/</>%s <yellow>(partial)</>\n", r.Service.Path, r.GoFileName)
 			} else if r.SdkAzureSdkGo {
 				c.Printf("    <gray>%s/</>%s \n", r.Service.Path, r.GoFileName)
@@ -80,23 +201,59 @@ func ListTrack1(v provider.Version) {
 	c.Printf("<red>%d</>/<yellow>%d</> resources and data sources  still using track1\n", toMigrate, total)
 }
 
+func ListTrack2(v provider.Version) {
+	total := 0
+	toMigrate := 0
+	for _, s := range v.Services {
+		t := s.CalculateTotals()
+		total += t.Resources()
+		total += t.DataSources()
+
+		if t.SdkTrack2() == 0 {
+			continue
+		}
+
+		toMigrate += t.SdkTrack2()
+
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> using track2)\n", s.Name, t.SdkTrack2(), t.Resources()+t.DataSources())
+		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
+			return rds.SdkAzureSdkGoTrack2
+		})
+
+		for _, r := range rds {
+			if r.MigrationState() == provider.MigrationPartial {
+				c.Printf("    <gray>%s/</>%s <yellow>(partial)</>\n", r.Service.Path, r.GoFileName)
+			} else {
+				c.Printf("    <gray>%s/</>%s \n", r.Service.Path, r.GoFileName)
+			}
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Println()
+
+	c.Printf("<red>%d</>/<yellow>%d</> resources and data sources still using track2\n", toMigrate, total)
+}
+
 func ListTyped(v provider.Version) {
 	total := 0
 	toMigrate := 0
 	for _, s := range v.Services {
 		t := s.CalculateTotals()
-		total += t.Resources
-		total += t.DataSources
+		total += t.Resources()
+		total += t.DataSources()
 
 		eTotal := s.CountResourcesDataSources()
 
-		if t.Typed == eTotal {
+		if t.Typed() == eTotal {
 			continue
 		}
 
-		toMigrate += eTotal - t.Typed
+		toMigrate += eTotal - t.Typed()
 
-		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> not typed)\n", s.Name, eTotal-t.Typed)
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> not typed)\n", s.Name, eTotal-t.Typed())
 
 		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
 			return !rds.IsTyped
@@ -120,16 +277,16 @@ func ListSharedCreateUpdate(v provider.Version) {
 	toMigrate := 0
 	for _, s := range v.Services {
 		t := s.CalculateTotals()
-		total += t.Resources
-		total += t.DataSources
+		total += t.Resources()
+		total += t.DataSources()
 
-		if t.CreateUpdate == 0 {
+		if t.CreateUpdate() == 0 {
 			continue
 		}
 
-		toMigrate += t.CreateUpdate
+		toMigrate += t.CreateUpdate()
 
-		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> is sharing a create/update function)\n", s.Name, t.CreateUpdate)
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> is sharing a create/update function)\n", s.Name, t.CreateUpdate())
 
 		rds := s.FilterResourcesDatasInterfaced(func(rds interface{}) bool {
 			if r, ok := rds.(provider.Resource); ok {
@@ -152,32 +309,137 @@ func ListSharedCreateUpdate(v provider.Version) {
 	c.Printf("<red>%d</>/<yellow>%d</> resources that need their shared create/update function split\n", toMigrate, total)
 }
 
-func ListBuiltInParse(v provider.Version) {
+func ListBuiltInParse(v provider.Version, ids provider.ResourceIDs) {
 	total := 0
 	toMigrate := 0
+	calls := 0
+	withoutEquivalent := 0
 	for _, s := range v.Services {
 		t := s.CalculateTotals()
-		total += t.Resources
-		total += t.DataSources
+		total += t.Resources()
+		total += t.DataSources()
 
-		if t.CreateUpdate == 0 {
+		if t.BuiltInParse() == 0 {
 			continue
 		}
 
-		toMigrate += t.CreateUpdate
+		toMigrate += t.BuiltInParse()
 
-		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> is sharing a create/update function)\n", s.Name, t.CreateUpdate)
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> using built in parse)\n", s.Name, t.BuiltInParse(), t.Resources()+t.DataSources())
 
-		rds := s.FilterResourcesDatasInterfaced(func(rds interface{}) bool {
-			if r, ok := rds.(provider.Resource); ok {
+		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
+			return rds.UsesBuiltInParse
+		})
 
-				return r.SharedCreateUpdate
+		for _, r := range rds {
+			c.Printf("    <gray>%s/</>%s\n", r.Service.Path, r.GoFileName)
+
+			for _, p := range r.BuiltInParsers {
+				calls++
+
+				equivalents := ids.EquivalentsOf(p)
+				if len(equivalents) == 0 {
+					withoutEquivalent++
+					c.Printf("        parse.%s <red>(no equivalent)</>\n", p)
+					continue
+				}
+
+				names := []string{}
+				for _, id := range equivalents {
+					names = append(names, id.String())
+				}
+				c.Printf("        parse.%s -> <green>%s</>\n", p, strings.Join(names, ", "))
 			}
-			return false
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Println()
+
+	c.Printf("<red>%d</>/<yellow>%d</> resources and data sources using built in parse, <red>%d</>/<yellow>%d</> parser calls without a commonids or go-azure-sdk equivalent\n", toMigrate, total, withoutEquivalent, calls)
+}
+
+func ListUnregistered(v provider.Version) {
+	unregistered := 0
+	missing := 0
+	for _, s := range v.Services {
+		rds := s.UnregisteredResourcesDatas()
+		regs := s.RegistrationsWithoutFile()
+
+		if len(rds) == 0 && len(regs) == 0 {
+			continue
+		}
+
+		unregistered += len(rds)
+		missing += len(regs)
+
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> files not registered, <lightMagenta>%d</> registrations without a file)\n", s.Name, len(rds), len(regs))
+
+		for _, r := range rds {
+			c.Printf("    <gray>%s/</>%s <yellow>(not registered)</>\n", r.Service.Path, r.GoFileName)
+		}
+
+		for _, r := range regs {
+			if r.Name != "" {
+				c.Printf("    <gray>%s/%s</> %s -> %s <red>(no file)</>\n", s.Path, s.RegistrationGoFileName, r.Name, r.Target)
+			} else {
+				c.Printf("    <gray>%s/%s</> %s <red>(no file)</>\n", s.Path, s.RegistrationGoFileName, r.Target)
+			}
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Println()
+
+	c.Printf("<red>%d</> resources and data sources not registered, <red>%d</> registrations without a file\n", unregistered, missing)
+}
+
+func ListTests(v provider.Version) {
+	total := 0
+	untested := 0
+	incomplete := 0
+	for _, s := range v.Services {
+		t := s.CalculateTotals()
+		eTotal := s.CountResourcesDataSources()
+		total += eTotal
+
+		// resources and data sources have different standard test cases
+		missing := map[string][]string{}
+		for _, r := range s.Resources {
+			if m := r.AccTests.MissingStandardResourceTests(); len(m) > 0 {
+				missing[r.GoFileName] = m
+			}
+		}
+		for _, d := range s.DataSources {
+			if m := d.AccTests.MissingStandardDataSourceTests(); len(m) > 0 {
+				missing[d.GoFileName] = m
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		untested += eTotal - t.Tested()
+		incomplete += len(missing)
+
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> tested, <lightMagenta>%d</> acceptance tests)\n", s.Name, t.Tested(), eTotal, t.AccTests())
+
+		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
+			_, ok := missing[rds.GoFileName]
+			return ok
 		})
 
 		for _, r := range rds {
-			c.Printf("    <gray>%s/</>%s \n", r.Service.Path, r.GoFileName)
+			if r.AccTests.Count() == 0 {
+				c.Printf("    <gray>%s/</>%s <red>(no tests)</>\n", r.Service.Path, r.GoFileName)
+			} else {
+				c.Printf("    <gray>%s/</>%s <yellow>(%d tests, missing %s)</>\n", r.Service.Path, r.GoFileName, r.AccTests.Count(), strings.Join(missing[r.GoFileName], ", "))
+			}
 		}
 
 		fmt.Println()
@@ -186,5 +448,112 @@ func ListBuiltInParse(v provider.Version) {
 	fmt.Println()
 	fmt.Println()
 
-	c.Printf("<red>%d</>/<yellow>%d</> resources that need their shared create/update function split\n", toMigrate, total)
+	c.Printf("<red>%d</>/<yellow>%d</> resources and data sources without tests, <red>%d</> missing standard test cases\n", untested, total, incomplete)
+}
+
+func ListClients(v provider.Version) {
+	withClients := 0
+	migrated := 0
+	legacy := 0
+	for _, s := range v.Services {
+		if len(s.Clients) == 0 {
+			continue
+		}
+		withClients++
+
+		if s.ClientsMigrated() {
+			migrated++
+			continue
+		}
+
+		clients := s.LegacyClients()
+		legacy += len(clients)
+
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> clients not using go-azure-sdk)\n", s.Name, len(clients), len(s.Clients))
+
+		for _, cl := range clients {
+			if len(cl.UsedBy) == 0 {
+				c.Printf("    %s <gray>%s</> <yellow>(%s)</> <red>unused</>\n", cl.Name, cl.Type, cl.Sdk)
+			} else {
+				c.Printf("    %s <gray>%s</> <yellow>(%s)</> %s\n", cl.Name, cl.Type, cl.Sdk, strings.Join(cl.UsedBy, ", "))
+			}
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Println()
+
+	c.Printf("<green>%d</>/<yellow>%d</> services with their clients fully migrated, <red>%d</> clients still to migrate\n", migrated, withClients, legacy)
+}
+
+func ListExcluded(v provider.Version) {
+	excluded := 0
+	services := 0
+	for _, s := range v.Services {
+		if len(s.Excluded) == 0 {
+			continue
+		}
+
+		excluded += len(s.Excluded)
+		services++
+
+		c.Printf(" <cyan>%s</> (<lightMagenta>%d</> files excluded)\n", s.Name, len(s.Excluded))
+
+		for _, e := range s.Excluded {
+			c.Printf("    <gray>%s/</>%s <yellow>%s</> %s\n", s.Path, e.File, e.Pattern, e.Reason)
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Println()
+
+	c.Printf("<red>%d</> files excluded in <yellow>%d</> services\n", excluded, services)
+}
+
+func ListDetected(v provider.Version, d provider.Detector) {
+	total := 0
+	matched := 0
+	for _, s := range v.Services {
+		total += s.CountResourcesDataSources()
+
+		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
+			return rds.Detected[d.Name] > 0
+		})
+
+		if len(rds) == 0 {
+			continue
+		}
+
+		matched += len(rds)
+		t := s.CalculateTotals()
+
+		if d.Count {
+			c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> %s, <lightMagenta>%d</> matches)\n", s.Name, len(rds), s.CountResourcesDataSources(), d.Name, t.Detected(d.Name))
+		} else {
+			c.Printf(" <cyan>%s</> (<lightMagenta>%d</>/<magenta>%d</> %s)\n", s.Name, len(rds), s.CountResourcesDataSources(), d.Name)
+		}
+
+		for _, r := range rds {
+			if d.Count {
+				c.Printf("    <gray>%s/</>%s <yellow>(%d)</>\n", r.Service.Path, r.GoFileName, r.Detected[d.Name])
+			} else {
+				c.Printf("    <gray>%s/</>%s \n", r.Service.Path, r.GoFileName)
+			}
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Println()
+
+	if d.Description != "" {
+		c.Printf("<red>%d</>/<yellow>%d</> resources and data sources matching %s (%s)\n", matched, total, d.Name, d.Description)
+	} else {
+		c.Printf("<red>%d</>/<yellow>%d</> resources and data sources matching %s\n", matched, total, d.Name)
+	}
 }
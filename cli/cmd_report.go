@@ -3,40 +3,102 @@ package cli
 import (
 	"fmt"
 	`log`
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	c "github.com/gookit/color" // nolint:misspell
 	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+	"github.com/katbyte/gogo-azurerm-info/lib/store"
 	"github.com/spf13/cobra"
 )
 
 func CmdReport(_ *cobra.Command, args []string) error {
 	repoPath := args[0]
+	f := GetFlags()
 
-	c.Printf("Scanning <cyan>%s</>... ", repoPath)
+	mode := ""
+	if len(args) > 1 {
+		mode = args[1]
+	}
+
+	if err := f.validateOutput(); err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig(f.Config)
+	if err != nil {
+		return err
+	}
+
+	if f.Output != "json" {
+		c.Printf("Scanning <cyan>%s</>... ", repoPath)
+	}
 
 	v := provider.Version{
 		Name: "main",
 		Path: repoPath,
 		Date: time.Time{},
+
+		ScanSchemas: mode == "schema",
+		Detectors:   cfg.Detectors,
+		Exclusions:  cfg.Exclusions,
+		Workers:     f.Workers,
+
+		ServicesPath: f.ServicesPath,
 	}
 
-	err := v.ScanServices()
+	err = v.ScanServices()
 	if err != nil {
 		return fmt.Errorf("scanning services: %w", err)
 	}
 
+	if f.Output == "json" {
+		// the whole tree, report types only differ in how they present it
+		return WriteJSON("report", mode, v, allElements)
+	}
+
 	t := v.CalculateTotals()
-	c.Printf(" <magenta>%d</> services with %d resources and %d data sources\n", len(v.Services), t.Resources, t.DataSources)
+	c.Printf(" <magenta>%d</> services with %d resources and %d data sources\n", len(v.Services), t.Resources(), t.DataSources())
+
+	defer PrintDiagnostics(v.Diagnostics())
 
 	if len(args) == 1 {
-		ReportDefault(v)
+		ReportDefault(v, cfg.Detectors)
+
+		// history is only known from versions graphs has cached
+		if f.Cache == "" {
+			c.Printf("\n<gray>set --cache to a database filled by graphs to project completion dates</>\n")
+			return nil
+		}
+
+		db, err := store.Open(f.Cache)
+		if err != nil {
+			return fmt.Errorf("opening cache: %w", err)
+		}
+		defer db.Close()
+
+		totals, err := db.Totals(v.RulesFingerprint())
+		if err != nil {
+			return fmt.Errorf("reading cached totals: %w", err)
+		}
+		ReportProjections(totals)
+
 		return nil
 	}
 
 	switch args[1] {
 	case "pandora-sdk-issue":
 		ReportPandoraSdkIssue(v)
+	case "schema":
+		ReportSchema(v)
+	case "api-versions":
+		ReportApiVersions(v)
+	case "api-age":
+		if err := ReportApiAge(v, f.GraphsPath); err != nil {
+			return fmt.Errorf("reporting api age: %w", err)
+		}
 	default:
 		return fmt.Errorf("unknown report type '%s': %w", args[1], err)
 	}
@@ -49,53 +111,110 @@ func CmdReport(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func ReportDefault(v provider.Version) {
+func ReportDefault(v provider.Version, detectors provider.Detectors) {
 	servicesEntirelyMigrated := make([]string, 0)
 	servicesPartiallyMigrated := make([]string, 0)
+	servicesNotStarted := make([]string, 0)
 	servicesUsingKermit := make([]string, 0)
 	servicesUsingTrack1 := make([]string, 0)
+	servicesUsingTrack2 := make([]string, 0)
 
 	for _, s := range v.Services {
 		t := s.CalculateTotals()
 
 		eCount := len(s.Resources) + len(s.DataSources)
 
-		// pandoraDone := (t.SdkPandora - t.SdkBoth) / eCount * 100
-
 		// light green 100% migrated
 		// light yellow partial
 		// light red 0
 
-		if t.SdkPandora > 0 && t.SdkTrack1 == 0 && t.SdkBoth == 0 {
+		switch t.MigrationState() {
+		case provider.MigrationDone:
 			servicesEntirelyMigrated = append(servicesEntirelyMigrated, s.Name)
 			continue
-		}
-		if t.SdkPandora == 0 && (t.SdkBoth >= 0 || t.SdkTrack1 >= 0) {
+		case provider.MigrationPartial:
 			servicesPartiallyMigrated = append(servicesPartiallyMigrated, s.Name)
+		case provider.MigrationNotStarted:
+			servicesNotStarted = append(servicesNotStarted, s.Name)
 		}
-		if t.SdkKermit > 0 {
+		if t.SdkKermit() > 0 {
 			servicesUsingKermit = append(servicesUsingKermit, s.Name)
 		}
-		if t.SdkTrack1 > 0 {
+		if t.SdkTrack1() > 0 {
 			servicesUsingTrack1 = append(servicesUsingTrack1, s.Name)
 		}
+		if t.SdkTrack2() > 0 {
+			servicesUsingTrack2 = append(servicesUsingTrack2, s.Name)
+		}
 
 		c.Printf(" <lightCyan>%s</> (<magenta>%d</> resources, <magenta>%d</> data sources)\n", s.Name, len(s.Resources), len(s.DataSources))
 
-		if t.SdkBoth != 0 {
-			c.Printf("    Pandora: %d / %d (%d partial)\n", t.SdkPandora-t.SdkBoth, eCount, t.SdkBoth)
+		if t.MigrationPartial() != 0 {
+			c.Printf("    Pandora: %d / %d (%d partial)\n", t.MigrationDone(), t.MigrationApplicable(), t.MigrationPartial())
 		} else {
-			c.Printf("    Pandora: %d / %d\n", t.SdkPandora-t.SdkBoth, eCount)
+			c.Printf("    Pandora: %d / %d\n", t.MigrationDone(), t.MigrationApplicable())
 		}
 
-		c.Printf("    Typed:   %d / %d\n", t.Typed, eCount)
+		if t.SdkTrack2() != 0 {
+			c.Printf("    Track2:  %d / %d\n", t.SdkTrack2(), eCount)
+		}
+
+		c.Printf("    Typed:   %d / %d\n", t.Typed(), eCount)
+		c.Printf("    Tested:  %d / %d (%d acceptance tests)\n", t.Tested(), eCount, t.AccTests())
+
+		for _, d := range detectors {
+			if d.Count {
+				c.Printf("    %s: %d\n", d.Name, t.Detected(d.Name))
+			} else {
+				c.Printf("    %s: %d / %d\n", d.Name, t.Detected(d.Name), eCount)
+			}
+		}
 		c.Printf("\n")
 	}
 
 	log.Printf("Services fully migrated to Pandora: %d", len(servicesEntirelyMigrated))
 	log.Printf("Services partially migrated to Pandora: %d", len(servicesPartiallyMigrated))
+	log.Printf("Services not started migrating to Pandora: %d", len(servicesNotStarted))
 	log.Printf("Services using Kermit: %d", len(servicesUsingKermit))
 	log.Printf("Services using Track1: %d", len(servicesUsingTrack1))
+	log.Printf("Services using Track2: %d", len(servicesUsingTrack2))
+}
+
+// ReportProjections prints when the migrations are projected to be done from the history of cached versions
+func ReportProjections(totals []store.VersionTotals) {
+	burndowns := []struct {
+		name      string
+		remaining func(t provider.Totals) int
+	}{
+		{"Pandora migration", provider.Totals.MigrationRemaining},
+		{"Track1 usage", provider.Totals.SdkTrack1},
+	}
+
+	fmt.Println()
+	for _, b := range burndowns {
+		points := make([]provider.BurndownPoint, 0, len(totals))
+		for _, t := range totals {
+			points = append(points, provider.BurndownPoint{Date: t.Date, Remaining: b.remaining(t.Totals)})
+		}
+
+		p := provider.ProjectBurndown(points)
+		if p.Done {
+			c.Printf("%s: <green>done</> as of %s\n", b.name, projectedDate(p.From.Date))
+			continue
+		}
+		if !p.Projected() {
+			c.Printf("%s: <yellow>no projection</> from %d cached versions\n", b.name, len(points))
+			continue
+		}
+
+		c.Printf("%s: <cyan>%d</> left as of %s, projected to reach zero <green>%s</> (<green>%s</> to <yellow>%s</>), linear fit %s\n",
+			b.name, p.From.Remaining, projectedDate(p.From.Date), projectedDate(p.Recent), projectedDate(p.Earliest), projectedDate(p.Latest), projectedDate(p.Linear))
+		window := len(points) - 1
+		if window > provider.ProjectionWindow {
+			window = provider.ProjectionWindow
+		}
+		c.Printf("    <gray>%.2f a day over the last %d releases, +/- %.2f</>\n", p.Velocity, window, p.Deviation)
+	}
 }
 
 func ReportPandoraSdkIssue(v provider.Version) {
@@ -103,33 +222,241 @@ func ReportPandoraSdkIssue(v provider.Version) {
 	fmt.Println("## Service Packages")
 	fmt.Println()
 
-	var servicesDone, servicesPartial, elementsTotal, elementsDone, elementsPartial int
+	var servicesTotal, servicesDone, servicesPartial, elementsTotal, elementsDone, elementsPartial, elementsLegacy, elementsTrack2 int
 	for _, s := range v.Services {
 		t := s.CalculateTotals()
 
-		eCount := len(s.Resources) + len(s.DataSources)
-
-		done := false
-		if t.SdkTrack1 == 0 && t.SdkBoth == 0 {
-			done = true
-			servicesDone++
+		// services without any sdk to migrate aren't part of the issue
+		state := t.MigrationState()
+		if state == provider.MigrationNotApplicable {
+			continue
 		}
 
-		if t.SdkBoth != 0 {
+		servicesTotal++
+		switch state {
+		case provider.MigrationDone:
+			servicesDone++
+		case provider.MigrationPartial:
 			servicesPartial++
 		}
 
+		eCount := t.MigrationApplicable()
 		elementsTotal += eCount
-		elementsDone += eCount - t.SdkTrack1
-		elementsPartial += t.SdkBoth
+		elementsDone += t.MigrationDone()
+		elementsPartial += t.MigrationPartial()
+		elementsLegacy += t.SdkLegacy()
+		elementsTrack2 += t.SdkTrack2()
 
-		if done {
+		if state == provider.MigrationDone {
 			fmt.Printf("- [X] `%s` _(%d)_\n", s.Name, eCount)
 		} else {
-			fmt.Printf("- [ ] `%s` _(%d/%d)_\n", s.Name, eCount-t.SdkTrack1, eCount)
+			fmt.Printf("- [ ] `%s` _(%d/%d)_\n", s.Name, t.MigrationDone(), eCount)
 		}
 	}
 
-	fmt.Printf("services: %d of %d (+%d partial)\n", servicesDone, len(v.Services), servicesPartial)
-	fmt.Printf("resources/datasources: %d of %d (-%d partial)\n", elementsDone, elementsTotal, elementsPartial)
+	fmt.Printf("services: %d of %d (+%d partial)\n", servicesDone, servicesTotal, servicesPartial)
+	fmt.Printf("resources/datasources: %d of %d (+%d partial)\n", elementsDone, elementsTotal, elementsPartial)
+	fmt.Printf("resources/datasources using a legacy sdk: %d\n", elementsLegacy)
+	fmt.Printf("resources/datasources using track2: %d\n", elementsTrack2)
+}
+
+func ReportSchema(v provider.Version) {
+	var total, required, optional, computed, forceNew, sensitive, helpers, unknown int
+
+	for _, s := range v.Services {
+		var sTotal, sRequired, sOptional, sComputed, sForceNew, sSensitive, sHelpers, sDepth int
+		sUnknown := []string{}
+
+		rds := s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool {
+			return true
+		})
+
+		for _, r := range rds {
+			if r.Schema == nil {
+				sUnknown = append(sUnknown, r.Name)
+				continue
+			}
+
+			for _, a := range r.Schema.Attributes {
+				sTotal++
+				if a.Required {
+					sRequired++
+				}
+				if a.Optional {
+					sOptional++
+				}
+				if a.Computed {
+					sComputed++
+				}
+				if a.ForceNew {
+					sForceNew++
+				}
+				if a.Sensitive {
+					sSensitive++
+				}
+				if a.Helper != "" {
+					sHelpers++
+				}
+			}
+
+			if d := r.Schema.MaxDepth(); d > sDepth {
+				sDepth = d
+			}
+		}
+
+		c.Printf(" <lightCyan>%s</> (<magenta>%d</> resources, <magenta>%d</> data sources)\n", s.Name, len(s.Resources), len(s.DataSources))
+		c.Printf("    Attributes: %d (%d required, %d optional, %d computed)\n", sTotal, sRequired, sOptional, sComputed)
+		c.Printf("    ForceNew:   %d\n", sForceNew)
+		c.Printf("    Sensitive:  %d\n", sSensitive)
+		c.Printf("    Max Depth:  %d\n", sDepth)
+		if sHelpers > 0 {
+			c.Printf("    Helpers:    %d <gray>(defined elsewhere, flags unknown)</>\n", sHelpers)
+		}
+		if len(sUnknown) > 0 {
+			c.Printf("    <yellow>No schema found:</> %s\n", strings.Join(sUnknown, ", "))
+		}
+		c.Printf("\n")
+
+		total += sTotal
+		required += sRequired
+		optional += sOptional
+		computed += sComputed
+		forceNew += sForceNew
+		sensitive += sSensitive
+		helpers += sHelpers
+		unknown += len(sUnknown)
+	}
+
+	log.Printf("Attributes: %d (%d required, %d optional, %d computed)", total, required, optional, computed)
+	log.Printf("ForceNew attributes: %d", forceNew)
+	log.Printf("Sensitive attributes: %d", sensitive)
+	log.Printf("Attributes from helpers: %d", helpers)
+	log.Printf("Resources/data sources without a schema found: %d", unknown)
+}
+
+func ReportApiVersions(v provider.Version) {
+	var servicesMixed, azureServicesMixed int
+
+	for _, s := range v.Services {
+		versions := s.ApiVersions()
+		if len(versions) == 0 {
+			continue
+		}
+
+		azureServices := make([]string, 0, len(versions))
+		for as := range versions {
+			azureServices = append(azureServices, as)
+		}
+		sort.Strings(azureServices)
+
+		mixed := 0
+		for _, as := range azureServices {
+			if len(versions[as]) > 1 {
+				mixed++
+			}
+		}
+
+		if mixed > 0 {
+			servicesMixed++
+			azureServicesMixed += mixed
+			c.Printf(" <lightCyan>%s</> <yellow>(mixing api versions of %d azure services)</>\n", s.Name, mixed)
+		} else {
+			c.Printf(" <lightCyan>%s</>\n", s.Name)
+		}
+
+		for _, as := range azureServices {
+			apiVersions := make([]string, 0, len(versions[as]))
+			for av := range versions[as] {
+				apiVersions = append(apiVersions, av)
+			}
+			sort.Strings(apiVersions)
+
+			if len(apiVersions) > 1 {
+				c.Printf("    <yellow>%s</>\n", as)
+			} else {
+				c.Printf("    %s\n", as)
+			}
+
+			for _, av := range apiVersions {
+				c.Printf("        %s <gray>(%d: %s)</>\n", av, len(versions[as][av]), strings.Join(versions[as][av], ", "))
+			}
+		}
+		c.Printf("\n")
+	}
+
+	log.Printf("Services mixing api versions: %d", servicesMixed)
+	log.Printf("Azure services used with more then one api version: %d", azureServicesMixed)
+}
+
+// apiAgeBuckets are the histogram buckets in years, the last one is open ended
+const apiAgeBuckets = 6
+
+func ReportApiAge(v provider.Version, outPath string) error {
+	// the working tree has no tag date
+	at := v.Date
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	type aged struct {
+		e   provider.ResourceOrData
+		api provider.SdkImport
+		age time.Duration
+	}
+
+	elements := []aged{}
+	for _, s := range v.Services {
+		for _, e := range s.FilterResourcesDatas(func(rds provider.ResourceOrData) bool { return true }) {
+			if api, ok := e.OldestSdkImport(); ok {
+				elements = append(elements, aged{e: e, api: api, age: api.Age(at)})
+			}
+		}
+	}
+
+	// oldest first
+	sort.SliceStable(elements, func(i, j int) bool {
+		return elements[i].age > elements[j].age
+	})
+
+	buckets := make([]int, apiAgeBuckets)
+	for i, a := range elements {
+		years := a.age.Hours() / 24 / 365
+		b := int(years)
+		if b >= apiAgeBuckets {
+			b = apiAgeBuckets - 1
+		}
+		if b < 0 {
+			b = 0
+		}
+		buckets[b]++
+
+		color := "green"
+		switch {
+		case years >= 3:
+			color = "red"
+		case years >= 2:
+			color = "yellow"
+		}
+
+		c.Printf(" %4d <%s>%4.1fy</> %s <gray>%s/%s (%s)</> %s\n", i+1, color, years, a.api.ApiVersion, a.api.Service, a.api.Package, a.api.Sdk, a.e.Name)
+	}
+	c.Printf("\n")
+
+	for i, n := range buckets {
+		log.Printf("%s: %d", apiAgeBucketName(i), n)
+	}
+
+	if err := os.MkdirAll(outPath, 0755); err != nil {
+		return fmt.Errorf("making path %s: %w", outPath, err)
+	}
+
+	return GraphApiAgeHistogram(v.Name, buckets, outPath)
+}
+
+func apiAgeBucketName(i int) string {
+	if i == apiAgeBuckets-1 {
+		return fmt.Sprintf("%d+ years", i)
+	}
+
+	return fmt.Sprintf("%d-%d years", i, i+1)
 }
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+	"github.com/spf13/viper"
+)
+
+// Config is the optional yaml/toml/json file passed with --config
+//
+//	detectors:
+//	  - name: kermit-client
+//	    description: uses a kermit client
+//	    type: import
+//	    pattern: github.com/tombuildsstuff/kermit/
+//	  - name: import-as-exists
+//	    type: call
+//	    pattern: tf.ImportAsExistsError
+//	    count: true
+//	exclusions:
+//	  - pattern: "*_base_resource.go"
+//	    reason: shared base of several resources
+//	  - pattern: "*migration_resource.go"
+//	    versions: "< 3.0.0"
+//	    reason: older schema migration
+//
+// exclusions replace the built in provider.DefaultExclusions when set
+type Config struct {
+	Detectors  provider.Detectors  `mapstructure:"detectors"`
+	Exclusions provider.Exclusions `mapstructure:"exclusions"`
+}
+
+// LoadConfig reads the config file at path, the format is picked from the extension
+func LoadConfig(path string) (*Config, error) {
+	cfg := Config{}
+	if path == "" {
+		return &cfg, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	// detectors can be listed by name so they can't shadow the built in lists
+	if err := cfg.Detectors.Compile(listNames()...); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := cfg.Exclusions.Compile(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+
+	c "github.com/gookit/color" // nolint:misspell
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+)
+
+// PrintDiagnostics summarises the files that could not be fully scanned, grouped by rule
+func PrintDiagnostics(diags []provider.Diagnostic) {
+	if len(diags) == 0 {
+		return
+	}
+
+	fmt.Println()
+	c.Printf("<yellow>%d</> diagnostics:\n", len(diags))
+
+	rules, byRule := provider.DiagnosticsByRule(diags)
+	for _, r := range rules {
+		c.Printf(" <yellow>%s</> (%d)\n", r, len(byRule[r]))
+		for _, d := range byRule[r] {
+			c.Printf("    <gray>%s</> %s\n", d.File, d.Message)
+		}
+	}
+}
@@ -1,17 +1,122 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 type FlagData struct {
+	Cache        string
+	Config       string
+	Output       string
+	Workers      int
+	ServicesPath string
+	GraphsPath   string
+	BreakingPath string
+	Charts       []string
+	Axis         string
 }
 
 func configureFlags(root *cobra.Command) error {
+	flags := FlagData{}
+	pflags := root.PersistentFlags()
+
+	pflags.StringVarP(&flags.Cache, "cache", "", "", "sqlite database to store scanned versions in so they are only scanned once")
+
+	if err := viper.BindPFlag("cache", pflags.Lookup("cache")); err != nil {
+		return fmt.Errorf("binding flag cache: %w", err)
+	}
+	if err := viper.BindEnv("cache", "CACHE_PATH"); err != nil {
+		return fmt.Errorf("binding env CACHE_PATH: %w", err)
+	}
+
+	pflags.StringVarP(&flags.Config, "config", "", "", "yaml, toml or json file with detector rules")
+	if err := viper.BindPFlag("config", pflags.Lookup("config")); err != nil {
+		return fmt.Errorf("binding flag config: %w", err)
+	}
+	if err := viper.BindEnv("config", "CONFIG_PATH"); err != nil {
+		return fmt.Errorf("binding env CONFIG_PATH: %w", err)
+	}
+
+	pflags.IntVarP(&flags.Workers, "workers", "", 0, "number of services to scan at once, defaults to the number of cpus")
+	if err := viper.BindPFlag("workers", pflags.Lookup("workers")); err != nil {
+		return fmt.Errorf("binding flag workers: %w", err)
+	}
+	if err := viper.BindEnv("workers", "WORKERS"); err != nil {
+		return fmt.Errorf("binding env WORKERS: %w", err)
+	}
+
+	pflags.StringVarP(&flags.ServicesPath, "services-path", "", "", "folder containing the service packages, found automatically when not set")
+	if err := viper.BindPFlag("services-path", pflags.Lookup("services-path")); err != nil {
+		return fmt.Errorf("binding flag services-path: %w", err)
+	}
+	if err := viper.BindEnv("services-path", "SERVICES_PATH"); err != nil {
+		return fmt.Errorf("binding env SERVICES_PATH: %w", err)
+	}
+
+	pflags.StringVarP(&flags.GraphsPath, "graphs-path", "", "graphs", "folder the graphs and their csv data are written to")
+	if err := viper.BindPFlag("graphs-path", pflags.Lookup("graphs-path")); err != nil {
+		return fmt.Errorf("binding flag graphs-path: %w", err)
+	}
+	if err := viper.BindEnv("graphs-path", "GRAPHS_PATH"); err != nil {
+		return fmt.Errorf("binding env GRAPHS_PATH: %w", err)
+	}
+
+	pflags.StringVarP(&flags.BreakingPath, "breaking-path", "", "breaking", "folder the breaking changes markdown and json are written to")
+	if err := viper.BindPFlag("breaking-path", pflags.Lookup("breaking-path")); err != nil {
+		return fmt.Errorf("binding flag breaking-path: %w", err)
+	}
+	if err := viper.BindEnv("breaking-path", "BREAKING_PATH"); err != nil {
+		return fmt.Errorf("binding env BREAKING_PATH: %w", err)
+	}
+
+	pflags.StringVarP(&flags.Output, "output", "o", "text", "output format for report and list: text or json")
+	if err := viper.BindPFlag("output", pflags.Lookup("output")); err != nil {
+		return fmt.Errorf("binding flag output: %w", err)
+	}
+
+	return nil
+}
+
+func configureGraphsFlags(graphs *cobra.Command) error {
+	flags := graphs.Flags()
+
+	flags.StringSliceP("charts", "", nil, "charts to render, defaults to all of: "+strings.Join(ChartNames(), ", "))
+	if err := viper.BindPFlag("charts", flags.Lookup("charts")); err != nil {
+		return fmt.Errorf("binding flag charts: %w", err)
+	}
+
+	flags.StringP("axis", "", string(ChartAxisTime), "plot trends against the version dates (time) or evenly spaced versions (version)")
+	if err := viper.BindPFlag("axis", flags.Lookup("axis")); err != nil {
+		return fmt.Errorf("binding flag axis: %w", err)
+	}
+
 	return nil
 }
 
 func GetFlags() FlagData {
 	// there has to be an easier way....
-	return FlagData{}
+	return FlagData{
+		Cache:        viper.GetString("cache"),
+		Config:       viper.GetString("config"),
+		Output:       viper.GetString("output"),
+		Workers:      viper.GetInt("workers"),
+		ServicesPath: viper.GetString("services-path"),
+		GraphsPath:   viper.GetString("graphs-path"),
+		BreakingPath: viper.GetString("breaking-path"),
+		Charts:       viper.GetStringSlice("charts"),
+		Axis:         viper.GetString("axis"),
+	}
+}
+
+func (f FlagData) validateOutput() error {
+	switch f.Output {
+	case "text", "json":
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format '%s', expected text or json", f.Output)
 }
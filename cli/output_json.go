@@ -0,0 +1,444 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+)
+
+// JSON output for `--output json`, other tools consume this so it is kept stable: fields are only ever added,
+// anything else bumps JSONSchemaVersion. Counts are always present, lists are empty rather then null.
+const JSONSchemaVersion = 1
+
+// JSONOutput is the document written by `report` and `list`
+type JSONOutput struct {
+	SchemaVersion int         `json:"schema_version"`
+	Command       string      `json:"command"`        // report or list
+	Mode          string      `json:"mode,omitempty"` // the report or list type, ie track1
+	Version       JSONVersion `json:"version"`
+}
+
+type JSONVersion struct {
+	Name        string           `json:"name"`
+	Hash        string           `json:"hash,omitempty"`
+	Date        *time.Time       `json:"date,omitempty"`
+	Path        string           `json:"path"`
+	Totals      JSONTotals       `json:"totals"` // always for the whole version, even when a list filters the elements
+	Services    []JSONService    `json:"services"`
+	Diagnostics []JSONDiagnostic `json:"diagnostics"` // files that could not be fully scanned
+}
+
+type JSONDiagnostic struct {
+	File    string `json:"file"`
+	Rule    string `json:"rule"` // ie parse, create-missing, create-multiple or update-multiple
+	Message string `json:"message"`
+}
+
+type JSONService struct {
+	Name           string         `json:"name"`
+	Path           string         `json:"path"`
+	MigrationState string         `json:"migration_state"` // not-started, partial, done or not-applicable
+	Totals         JSONTotals     `json:"totals"`
+	Clients        []JSONClient   `json:"clients"`  // always every client, even when a list filters the elements
+	Excluded       []JSONExcluded `json:"excluded"` // files skipped by an exclusion
+	Resources      []JSONElement  `json:"resources"`
+	DataSources    []JSONElement  `json:"data_sources"`
+}
+
+type JSONExcluded struct {
+	File    string `json:"file"`
+	Pattern string `json:"pattern"`
+	Reason  string `json:"reason"`
+}
+
+// JSONClient is a field on the service's client.Client struct
+type JSONClient struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`   // ie compute.VirtualMachinesClient
+	Import string   `json:"import"` // empty when declared in the client package
+	Sdk    string   `json:"sdk"`    // track1, track2, pandora, kermit, giovanni or empty
+	UsedBy []string `json:"used_by"`
+}
+
+// JSONElement is a resource or data source
+type JSONElement struct {
+	Name       string `json:"name"` // terraform type, ie azurerm_resource_group
+	GoFileName string `json:"go_file_name"`
+	GoPath     string `json:"go_path"`
+
+	Typed      bool `json:"typed"`
+	Generated  bool `json:"generated"`
+	Registered bool `json:"registered"`
+
+	MigrationState string `json:"migration_state"` // not-started, partial, done or not-applicable
+
+	Sdks       JSONSdks        `json:"sdks"`
+	SdkImports []JSONSdkImport `json:"sdk_imports"` // versioned api packages imported
+
+	UsesBuiltInParse   bool     `json:"uses_built_in_parse"`
+	BuiltInParsers     []string `json:"built_in_parsers"`     // functions called on the service's parse package
+	SharedCreateUpdate bool     `json:"shared_create_update"` // always false for data sources
+
+	Detected map[string]int `json:"detected"` // config detectors that matched, 1 unless the detector counts
+
+	Tests  JSONTests   `json:"tests"`
+	Schema *JSONSchema `json:"schema,omitempty"` // only for `report schema`, null when it couldn't be found
+
+	Parsers []JSONParser `json:"parsers,omitempty"` // only for `list built-in-parse`, one per built in parser
+}
+
+// JSONParser is a function called on a parse package and the resource ids that could replace it
+type JSONParser struct {
+	Name        string           `json:"name"`        // ie VirtualMachineID
+	Equivalents []JSONResourceID `json:"equivalents"` // commonids and go-azure-sdk ids, empty when there are none
+}
+
+type JSONResourceID struct {
+	Package string `json:"package"` // import path, ie github.com/hashicorp/go-azure-helpers/resourcemanager/commonids
+	Func    string `json:"func"`    // ie ParseVirtualMachineID
+}
+
+type JSONSdks struct {
+	Track1   bool `json:"track1"`
+	Track2   bool `json:"track2"`
+	Pandora  bool `json:"pandora"`
+	Kermit   bool `json:"kermit"`
+	Giovanni bool `json:"giovanni"`
+}
+
+type JSONSdkImport struct {
+	Sdk        string `json:"sdk"` // pandora or track1
+	Path       string `json:"path"`
+	Service    string `json:"service"`     // azure service, ie compute
+	ApiVersion string `json:"api_version"` // ie 2022-03-01
+	Package    string `json:"package"`     // ie virtualmachines, empty for an api version's meta client
+}
+
+type JSONTests struct {
+	Paths             []string `json:"paths"`
+	Count             int      `json:"count"`
+	Names             []string `json:"names"`
+	HasBasic          bool     `json:"has_basic"`
+	HasRequiresImport bool     `json:"has_requires_import"`
+	HasComplete       bool     `json:"has_complete"`
+	HasUpdate         bool     `json:"has_update"`
+}
+
+type JSONSchema struct {
+	Attributes []JSONAttribute  `json:"attributes"` // flattened, nested blocks follow their parent
+	Model      []JSONModelField `json:"model"`      // typed only
+}
+
+type JSONAttribute struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`  // dotted path from the top level
+	Depth     int    `json:"depth"` // 0 for top level
+	Type      string `json:"type"`  // String, Int, Bool, Float, List, Set, Map or empty when unknown
+	ElemType  string `json:"elem_type,omitempty"`
+	Required  bool   `json:"required"`
+	Optional  bool   `json:"optional"`
+	Computed  bool   `json:"computed"`
+	ForceNew  bool   `json:"force_new"`
+	Sensitive bool   `json:"sensitive"`
+	Helper    string `json:"helper,omitempty"` // set when defined by a function elsewhere and the flags are unknown
+}
+
+type JSONModelField struct {
+	Struct   string `json:"struct"`
+	Field    string `json:"field"`
+	GoType   string `json:"go_type"`
+	TfSchema string `json:"tfschema"`
+}
+
+type JSONTotals struct {
+	Services     int `json:"services"`
+	Resources    int `json:"resources"`
+	DataSources  int `json:"data_sources"`
+	SdkTrack1    int `json:"sdk_track1"`
+	SdkTrack2    int `json:"sdk_track2"`
+	SdkPandora   int `json:"sdk_pandora"`
+	SdkKermit    int `json:"sdk_kermit"`
+	SdkGiovanni  int `json:"sdk_giovanni"`
+	SdkBoth      int `json:"sdk_both"`   // pandora and a legacy sdk, the same as migration_partial
+	SdkLegacy    int `json:"sdk_legacy"` // track1, track2 or kermit
+	Typed        int `json:"typed"`
+	CreateUpdate int `json:"shared_create_update"`
+	BuiltInParse int `json:"built_in_parse"`
+	Tested       int `json:"tested"`
+	AccTests     int `json:"acc_tests"`
+
+	MigrationNotStarted    int `json:"migration_not_started"`
+	MigrationPartial       int `json:"migration_partial"`
+	MigrationDone          int `json:"migration_done"`
+	MigrationNotApplicable int `json:"migration_not_applicable"`
+
+	Detected map[string]int `json:"detected"` // config detector totals
+}
+
+// elementFilter is passed a provider.Resource or provider.DataSource
+type elementFilter func(e interface{}) bool
+
+func allElements(_ interface{}) bool {
+	return true
+}
+
+// WriteJSON writes the version to stdout including only the resources and data sources that pass filter
+func WriteJSON(command, mode string, v provider.Version, filter elementFilter) error {
+	return NewJSONOutput(command, mode, v, filter).Write()
+}
+
+func NewJSONOutput(command, mode string, v provider.Version, filter elementFilter) JSONOutput {
+	return JSONOutput{
+		SchemaVersion: JSONSchemaVersion,
+		Command:       command,
+		Mode:          mode,
+		Version:       NewJSONVersion(v, filter),
+	}
+}
+
+// Write encodes the output to stdout
+func (out JSONOutput) Write() error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encoding json: %w", err)
+	}
+
+	return nil
+}
+
+func NewJSONVersion(v provider.Version, filter elementFilter) JSONVersion {
+	jv := JSONVersion{
+		Name:     v.Name,
+		Hash:     v.Hash,
+		Path:     v.Path,
+		Totals:   NewJSONTotals(v.CalculateTotals()),
+		Services: []JSONService{},
+
+		Diagnostics: []JSONDiagnostic{},
+	}
+
+	for _, d := range v.Diagnostics() {
+		jv.Diagnostics = append(jv.Diagnostics, JSONDiagnostic{
+			File:    d.File,
+			Rule:    d.Rule,
+			Message: d.Message,
+		})
+	}
+
+	if !v.Date.IsZero() {
+		jv.Date = &v.Date
+	}
+
+	for _, s := range v.Services {
+		js := JSONService{
+			Name:   s.Name,
+			Path:   s.Path,
+			Totals: NewJSONTotals(s.CalculateTotals()),
+
+			MigrationState: s.MigrationState().String(),
+			Clients:        []JSONClient{},
+			Excluded:       []JSONExcluded{},
+			Resources:      []JSONElement{},
+			DataSources:    []JSONElement{},
+		}
+
+		for _, cl := range s.Clients {
+			js.Clients = append(js.Clients, JSONClient{
+				Name:   cl.Name,
+				Type:   cl.Type,
+				Import: cl.Import,
+				Sdk:    cl.Sdk,
+				UsedBy: append([]string{}, cl.UsedBy...),
+			})
+		}
+
+		for _, e := range s.Excluded {
+			js.Excluded = append(js.Excluded, JSONExcluded{
+				File:    e.File,
+				Pattern: e.Pattern,
+				Reason:  e.Reason,
+			})
+		}
+
+		for _, r := range s.Resources {
+			if filter(r) {
+				je := NewJSONElement(r.ResourceOrData)
+				je.SharedCreateUpdate = r.SharedCreateUpdate
+				js.Resources = append(js.Resources, je)
+			}
+		}
+
+		for _, d := range s.DataSources {
+			if filter(d) {
+				js.DataSources = append(js.DataSources, NewJSONElement(d.ResourceOrData))
+			}
+		}
+
+		jv.Services = append(jv.Services, js)
+	}
+
+	return jv
+}
+
+func NewJSONElement(e provider.ResourceOrData) JSONElement {
+	je := JSONElement{
+		Name:       e.Name,
+		GoFileName: e.GoFileName,
+		GoPath:     e.GoPath,
+		Typed:      e.IsTyped,
+		Generated:  e.IsGenerated,
+		Registered: e.IsRegistered,
+
+		MigrationState: e.MigrationState().String(),
+		Sdks: JSONSdks{
+			Track1:   e.SdkAzureSdkGo,
+			Track2:   e.SdkAzureSdkGoTrack2,
+			Pandora:  e.SdkPandora,
+			Kermit:   e.SdkKermit,
+			Giovanni: e.SdkGiovanni,
+		},
+		SdkImports:       []JSONSdkImport{},
+		Detected:         map[string]int{},
+		UsesBuiltInParse: e.UsesBuiltInParse,
+		BuiltInParsers:   []string{},
+		Tests: JSONTests{
+			Paths:             []string{},
+			Count:             e.AccTests.Count(),
+			Names:             []string{},
+			HasBasic:          e.AccTests.HasBasic,
+			HasRequiresImport: e.AccTests.HasRequiresImport,
+			HasComplete:       e.AccTests.HasComplete,
+			HasUpdate:         e.AccTests.HasUpdate,
+		},
+	}
+
+	if e.Schema != nil {
+		je.Schema = NewJSONSchema(*e.Schema)
+	}
+
+	for _, i := range e.SdkImports {
+		je.SdkImports = append(je.SdkImports, JSONSdkImport{
+			Sdk:        i.Sdk,
+			Path:       i.Path,
+			Service:    i.Service,
+			ApiVersion: i.ApiVersion,
+			Package:    i.Package,
+		})
+	}
+
+	for k, n := range e.Detected {
+		je.Detected[k] = n
+	}
+
+	je.BuiltInParsers = append(je.BuiltInParsers, e.BuiltInParsers...)
+	je.Tests.Paths = append(je.Tests.Paths, e.TestPaths...)
+	je.Tests.Names = append(je.Tests.Names, e.AccTests.Names...)
+
+	return je
+}
+
+// AddParsers lists each element's built in parsers with their equivalents from ids
+func (jv *JSONVersion) AddParsers(ids provider.ResourceIDs) {
+	add := func(elements []JSONElement) {
+		for i := range elements {
+			e := &elements[i]
+			e.Parsers = []JSONParser{}
+
+			for _, p := range e.BuiltInParsers {
+				jp := JSONParser{Name: p, Equivalents: []JSONResourceID{}}
+				for _, id := range ids.EquivalentsOf(p) {
+					jp.Equivalents = append(jp.Equivalents, JSONResourceID{Package: id.Package, Func: id.Func})
+				}
+				e.Parsers = append(e.Parsers, jp)
+			}
+		}
+	}
+
+	for i := range jv.Services {
+		add(jv.Services[i].Resources)
+		add(jv.Services[i].DataSources)
+	}
+}
+
+func NewJSONSchema(s provider.Schema) *JSONSchema {
+	js := JSONSchema{
+		Attributes: []JSONAttribute{},
+		Model:      []JSONModelField{},
+	}
+
+	for _, a := range s.Attributes {
+		js.Attributes = append(js.Attributes, JSONAttribute{
+			Name:      a.Name,
+			Path:      a.Path,
+			Depth:     a.Depth,
+			Type:      a.Type,
+			ElemType:  a.ElemType,
+			Required:  a.Required,
+			Optional:  a.Optional,
+			Computed:  a.Computed,
+			ForceNew:  a.ForceNew,
+			Sensitive: a.Sensitive,
+			Helper:    a.Helper,
+		})
+	}
+
+	for _, f := range s.Model {
+		js.Model = append(js.Model, JSONModelField{
+			Struct:   f.Struct,
+			Field:    f.Field,
+			GoType:   f.GoType,
+			TfSchema: f.TfSchema,
+		})
+	}
+
+	return &js
+}
+
+func NewJSONTotals(t provider.Totals) JSONTotals {
+	jt := JSONTotals{
+		Services:     t.Services(),
+		Resources:    t.Resources(),
+		DataSources:  t.DataSources(),
+		SdkTrack1:    t.SdkTrack1(),
+		SdkTrack2:    t.SdkTrack2(),
+		SdkPandora:   t.SdkPandora(),
+		SdkKermit:    t.SdkKermit(),
+		SdkGiovanni:  t.SdkGiovanni(),
+		SdkBoth:      t.SdkBoth(),
+		SdkLegacy:    t.SdkLegacy(),
+		Typed:        t.Typed(),
+		CreateUpdate: t.CreateUpdate(),
+		BuiltInParse: t.BuiltInParse(),
+		Tested:       t.Tested(),
+		AccTests:     t.AccTests(),
+
+		MigrationNotStarted:    t.MigrationNotStarted(),
+		MigrationPartial:       t.MigrationPartial(),
+		MigrationDone:          t.MigrationDone(),
+		MigrationNotApplicable: t.MigrationNotApplicable(),
+
+		Detected: map[string]int{},
+	}
+
+	for _, d := range t.DetectedMetrics() {
+		jt.Detected[d] = t.Detected(d)
+	}
+
+	return jt
+}
+
+// elementOf returns the ResourceOrData of a provider.Resource or provider.DataSource
+func elementOf(e interface{}) provider.ResourceOrData {
+	switch e := e.(type) {
+	case provider.Resource:
+		return e.ResourceOrData
+	case provider.DataSource:
+		return e.ResourceOrData
+	}
+
+	return provider.ResourceOrData{}
+}
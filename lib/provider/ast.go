@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseGoFile parses a go source file keeping comments around so generation markers can be detected
+func parseGoFile(name string, content []byte) (*ast.File, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), name, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	return f, nil
+}
+
+// fileImports returns a map of import path -> the local name it is referred to by in the file
+func fileImports(f *ast.File) map[string]string {
+	imports := map[string]string{}
+
+	for _, i := range f.Imports {
+		if path, name, ok := importOf(i); ok {
+			imports[path] = name
+		}
+	}
+
+	return imports
+}
+
+// importOf returns the path of an import and the local name it is referred to by
+func importOf(i *ast.ImportSpec) (string, string, bool) {
+	path, err := strconv.Unquote(i.Path.Value)
+	if err != nil {
+		return "", "", false
+	}
+
+	name := path[strings.LastIndex(path, "/")+1:]
+	if i.Name != nil {
+		name = i.Name.Name
+	}
+
+	return path, name, true
+}
+
+// importNameWithSuffix returns the local name of the first import in the file ending in one of suffixes, trying each
+// suffix in turn so the earlier ones are preferred
+func importNameWithSuffix(f *ast.File, suffixes ...string) (string, bool) {
+	for _, suffix := range suffixes {
+		for _, i := range f.Imports {
+			if path, name, ok := importOf(i); ok && strings.HasSuffix(path, suffix) {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// isSelector checks if the expression is pkg.<name with prefix>
+func isSelector(e ast.Expr, pkg, prefix string) bool {
+	s, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	x, ok := s.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	return x.Name == pkg && strings.HasPrefix(s.Sel.Name, prefix)
+}
+
+// calledFuncs returns the sorted unique functions called on the pkg import, ie pkg.Func()
+func calledFuncs(f *ast.File, pkg string) []string {
+	seen := map[string]bool{}
+	funcs := []string{}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		s, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !isSelector(s, pkg, "") || seen[s.Sel.Name] {
+			return true
+		}
+
+		seen[s.Sel.Name] = true
+		funcs = append(funcs, s.Sel.Name)
+
+		return true
+	})
+
+	sort.Strings(funcs)
+
+	return funcs
+}
+
+// structTagValue returns the value of key in a raw (quoted) struct field tag
+func structTagValue(tag *ast.BasicLit, key string) (string, bool) {
+	if tag == nil {
+		return "", false
+	}
+
+	raw, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return reflect.StructTag(raw).Lookup(key)
+}
+
+// hasCommentContaining checks all comments in the file for text, comments inside string literals are not included
+func hasCommentContaining(f *ast.File, text string) bool {
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if strings.Contains(c.Text, text) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// keyedIdents returns the identifiers assigned to key in all composite literals, ie `Create: resourceCreate,`
+func keyedIdents(f *ast.File, key string) []string {
+	idents := []string{}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+
+		k, ok := kv.Key.(*ast.Ident)
+		if !ok || k.Name != key {
+			return true
+		}
+
+		if v, ok := kv.Value.(*ast.Ident); ok {
+			idents = append(idents, v.Name)
+		}
+
+		return true
+	})
+
+	return idents
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"sort"
+	"strings"
+)
+
+type BreakingChangeKind string
+
+const (
+	BreakingResourceRemoved      BreakingChangeKind = "resource-removed"
+	BreakingDataSourceRemoved    BreakingChangeKind = "data-source-removed"
+	BreakingAttributeRemoved     BreakingChangeKind = "attribute-removed"
+	BreakingAttributeRenamed     BreakingChangeKind = "attribute-renamed" // a guess, removed & added in the same block with the same type
+	BreakingAttributeNowRequired BreakingChangeKind = "attribute-now-required"
+	BreakingAttributeNowForceNew BreakingChangeKind = "attribute-now-force-new"
+	BreakingAttributeTypeChanged BreakingChangeKind = "attribute-type-changed"
+)
+
+type BreakingChange struct {
+	Kind       BreakingChangeKind `json:"kind"`
+	Name       string             `json:"name"` // resource or data source
+	DataSource bool               `json:"data_source"`
+	Attribute  string             `json:"attribute,omitempty"`
+	From       string             `json:"from,omitempty"`
+	To         string             `json:"to,omitempty"`
+}
+
+type BreakingChanges struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Changes []BreakingChange `json:"changes"`
+}
+
+// CompareSchemas finds the breaking changes between two versions scanned with ScanSchemas
+func CompareSchemas(from, to *Version) BreakingChanges {
+	bc := BreakingChanges{
+		From:    from.Name,
+		To:      to.Name,
+		Changes: []BreakingChange{},
+	}
+
+	fromResources, fromDataSources := from.elementsByName()
+	toResources, toDataSources := to.elementsByName()
+
+	bc.Changes = append(bc.Changes, compareElements(fromResources, toResources, false)...)
+	bc.Changes = append(bc.Changes, compareElements(fromDataSources, toDataSources, true)...)
+
+	sort.SliceStable(bc.Changes, func(i, j int) bool {
+		if bc.Changes[i].DataSource != bc.Changes[j].DataSource {
+			return !bc.Changes[i].DataSource
+		}
+		return bc.Changes[i].Name < bc.Changes[j].Name
+	})
+
+	return bc
+}
+
+func (v *Version) elementsByName() (map[string]ResourceOrData, map[string]ResourceOrData) {
+	resources := map[string]ResourceOrData{}
+	dataSources := map[string]ResourceOrData{}
+
+	for _, s := range v.Services {
+		for _, r := range s.Resources {
+			resources[r.Name] = r.ResourceOrData
+		}
+		for _, d := range s.DataSources {
+			dataSources[d.Name] = d.ResourceOrData
+		}
+	}
+
+	return resources, dataSources
+}
+
+func compareElements(from, to map[string]ResourceOrData, dataSource bool) []BreakingChange {
+	changes := []BreakingChange{}
+
+	for name, f := range from {
+		t, ok := to[name]
+		if !ok {
+			kind := BreakingResourceRemoved
+			if dataSource {
+				kind = BreakingDataSourceRemoved
+			}
+
+			changes = append(changes, BreakingChange{
+				Kind:       kind,
+				Name:       name,
+				DataSource: dataSource,
+			})
+			continue
+		}
+
+		// can't compare what we couldn't find
+		if f.Schema == nil || t.Schema == nil {
+			continue
+		}
+
+		for _, c := range compareAttributes(*f.Schema, *t.Schema, dataSource) {
+			c.Name = name
+			c.DataSource = dataSource
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+func compareAttributes(from, to Schema, dataSource bool) []BreakingChange {
+	changes := []BreakingChange{}
+
+	removed := []Attribute{}
+	for _, fa := range from.Attributes {
+		ta, ok := to.Attribute(fa.Path)
+		if !ok {
+			removed = append(removed, fa)
+			continue
+		}
+
+		// helpers are defined elsewhere so we don't know their flags
+		if fa.Helper != "" || ta.Helper != "" {
+			continue
+		}
+
+		if !fa.Required && ta.Required {
+			changes = append(changes, BreakingChange{
+				Kind:      BreakingAttributeNowRequired,
+				Attribute: fa.Path,
+			})
+		}
+
+		if !dataSource && !fa.ForceNew && ta.ForceNew {
+			changes = append(changes, BreakingChange{
+				Kind:      BreakingAttributeNowForceNew,
+				Attribute: fa.Path,
+			})
+		}
+
+		if fa.typeString() != ta.typeString() && fa.Type != "" && ta.Type != "" {
+			changes = append(changes, BreakingChange{
+				Kind:      BreakingAttributeTypeChanged,
+				Attribute: fa.Path,
+				From:      fa.typeString(),
+				To:        ta.typeString(),
+			})
+		}
+	}
+
+	added := []Attribute{}
+	for _, ta := range to.Attributes {
+		if _, ok := from.Attribute(ta.Path); !ok {
+			added = append(added, ta)
+		}
+	}
+
+	removedPaths := map[string]bool{}
+	for _, r := range removed {
+		removedPaths[r.Path] = true
+	}
+
+	renamedTo := map[string]bool{}
+	for _, r := range removed {
+		// only report the top most removed block
+		if removedPaths[r.parentPath()] {
+			continue
+		}
+
+		// a single new attribute in the same block with the same type is most likely a rename
+		candidates := []Attribute{}
+		for _, a := range added {
+			if a.parentPath() == r.parentPath() && a.typeString() == r.typeString() && !renamedTo[a.Path] {
+				candidates = append(candidates, a)
+			}
+		}
+
+		if len(candidates) == 1 {
+			renamedTo[candidates[0].Path] = true
+			changes = append(changes, BreakingChange{
+				Kind:      BreakingAttributeRenamed,
+				Attribute: r.Path,
+				From:      r.Name,
+				To:        candidates[0].Name,
+			})
+			continue
+		}
+
+		changes = append(changes, BreakingChange{
+			Kind:      BreakingAttributeRemoved,
+			Attribute: r.Path,
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Attribute < changes[j].Attribute
+	})
+
+	return changes
+}
+
+func (a Attribute) typeString() string {
+	if a.ElemType != "" {
+		return a.Type + "(" + a.ElemType + ")"
+	}
+	return a.Type
+}
+
+func (a Attribute) parentPath() string {
+	if i := strings.LastIndex(a.Path, "."); i >= 0 {
+		return a.Path[:i]
+	}
+	return ""
+}
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// attr builds an optional attribute at path, options are applied in order
+func attr(path, typ string, options ...func(*Attribute)) Attribute {
+	a := Attribute{
+		Name:     path[strings.LastIndex(path, ".")+1:],
+		Path:     path,
+		Depth:    strings.Count(path, "."),
+		Type:     typ,
+		Optional: true,
+	}
+
+	for _, o := range options {
+		o(&a)
+	}
+
+	return a
+}
+
+func required(a *Attribute) { a.Required, a.Optional = true, false }
+func forceNew(a *Attribute) { a.ForceNew = true }
+
+func elemType(t string) func(*Attribute) {
+	return func(a *Attribute) { a.ElemType = t }
+}
+
+func helper(name string) func(*Attribute) {
+	return func(a *Attribute) { a.Helper, a.Type = name, "" }
+}
+
+func TestCompareAttributes(t *testing.T) {
+	cases := []struct {
+		name       string
+		from       []Attribute
+		to         []Attribute
+		dataSource bool
+		expected   []BreakingChange
+	}{
+		{
+			name: "unchanged",
+			from: []Attribute{attr("name", "String", required), attr("tags", "Map", elemType("String"))},
+			to:   []Attribute{attr("name", "String", required), attr("tags", "Map", elemType("String"))},
+		},
+		{
+			name: "optional attribute added",
+			from: []Attribute{attr("name", "String", required)},
+			to:   []Attribute{attr("name", "String", required), attr("zone", "String")},
+		},
+		{
+			name: "no longer required",
+			from: []Attribute{attr("name", "String", required)},
+			to:   []Attribute{attr("name", "String")},
+		},
+		{
+			name: "removed",
+			from: []Attribute{attr("name", "String"), attr("zone", "String")},
+			to:   []Attribute{attr("name", "String")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "zone"},
+			},
+		},
+		{
+			name: "renamed",
+			from: []Attribute{attr("name", "String"), attr("zone", "String")},
+			to:   []Attribute{attr("name", "String"), attr("zones", "String")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRenamed, Attribute: "zone", From: "zone", To: "zones"},
+			},
+		},
+		{
+			name: "added with another type is not a rename",
+			from: []Attribute{attr("zone", "String")},
+			to:   []Attribute{attr("zones", "List", elemType("String"))},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "zone"},
+			},
+		},
+		{
+			name: "added in another block is not a rename",
+			from: []Attribute{attr("zone", "String"), attr("network", "List", elemType("Block"))},
+			to:   []Attribute{attr("network", "List", elemType("Block")), attr("network.zone", "String")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "zone"},
+			},
+		},
+		{
+			name: "more then one candidate is not a rename",
+			from: []Attribute{attr("zone", "String")},
+			to:   []Attribute{attr("zone_name", "String"), attr("zone_id", "String")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "zone"},
+			},
+		},
+		{
+			name: "only the removed block is reported",
+			from: []Attribute{
+				attr("name", "String"),
+				attr("os_disk", "List", elemType("Block")),
+				attr("os_disk.caching", "String"),
+				attr("os_disk.size", "Int"),
+			},
+			to: []Attribute{attr("name", "String")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "os_disk"},
+			},
+		},
+		{
+			name: "nested attribute removed",
+			from: []Attribute{attr("os_disk", "List", elemType("Block")), attr("os_disk.caching", "String"), attr("os_disk.size", "Int")},
+			to:   []Attribute{attr("os_disk", "List", elemType("Block")), attr("os_disk.size", "Int")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "os_disk.caching"},
+			},
+		},
+		{
+			name: "now required",
+			from: []Attribute{attr("name", "String")},
+			to:   []Attribute{attr("name", "String", required)},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeNowRequired, Attribute: "name"},
+			},
+		},
+		{
+			name: "new required attribute",
+			from: []Attribute{attr("name", "String")},
+			to:   []Attribute{attr("name", "String"), attr("sku", "String", required)},
+		},
+		{
+			name: "now force new",
+			from: []Attribute{attr("location", "String")},
+			to:   []Attribute{attr("location", "String", forceNew)},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeNowForceNew, Attribute: "location"},
+			},
+		},
+		{
+			name:       "force new doesn't matter for data sources",
+			from:       []Attribute{attr("location", "String")},
+			to:         []Attribute{attr("location", "String", forceNew)},
+			dataSource: true,
+		},
+		{
+			name: "now required and force new",
+			from: []Attribute{attr("location", "String")},
+			to:   []Attribute{attr("location", "String", required, forceNew)},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeNowRequired, Attribute: "location"},
+				{Kind: BreakingAttributeNowForceNew, Attribute: "location"},
+			},
+		},
+		{
+			name: "type changed",
+			from: []Attribute{attr("size", "String")},
+			to:   []Attribute{attr("size", "Int")},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeTypeChanged, Attribute: "size", From: "String", To: "Int"},
+			},
+		},
+		{
+			name: "element type changed",
+			from: []Attribute{attr("zones", "List", elemType("String"))},
+			to:   []Attribute{attr("zones", "List", elemType("Int"))},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeTypeChanged, Attribute: "zones", From: "List(String)", To: "List(Int)"},
+			},
+		},
+		{
+			name: "unknown type isn't a change",
+			from: []Attribute{attr("size", "String")},
+			to:   []Attribute{attr("size", "")},
+		},
+		{
+			name: "helpers are not compared",
+			from: []Attribute{attr("location", "String")},
+			to:   []Attribute{attr("location", "", helper("commonschema.Location"), required, forceNew)},
+		},
+		{
+			name: "sorted by attribute",
+			from: []Attribute{attr("b", "String"), attr("a", "String"), attr("c", "String")},
+			to:   []Attribute{attr("c", "Int"), attr("b", "String", required)},
+			expected: []BreakingChange{
+				{Kind: BreakingAttributeRemoved, Attribute: "a"},
+				{Kind: BreakingAttributeNowRequired, Attribute: "b"},
+				{Kind: BreakingAttributeTypeChanged, Attribute: "c", From: "String", To: "Int"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compareAttributes(Schema{Attributes: tc.from}, Schema{Attributes: tc.to}, tc.dataSource)
+
+			expected := tc.expected
+			if expected == nil {
+				expected = []BreakingChange{}
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected %+v, got %+v", expected, got)
+			}
+		})
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	element := func(name string, attrs ...Attribute) ResourceOrData {
+		rds := ResourceOrData{Name: name}
+		if attrs != nil {
+			rds.Schema = &Schema{Attributes: attrs}
+		}
+		return rds
+	}
+
+	from := &Version{Name: "v3.0.0", Services: []Service{
+		{
+			Name: "compute",
+			Resources: []Resource{
+				{ResourceOrData: element("azurerm_virtual_machine", attr("name", "String"), attr("zone", "String"))},
+				{ResourceOrData: element("azurerm_availability_set", attr("name", "String"))},
+				{ResourceOrData: element("azurerm_unknown")},
+			},
+			DataSources: []DataSource{
+				{ResourceOrData: element("azurerm_virtual_machine", attr("name", "String"))},
+			},
+		},
+		{
+			Name: "network",
+			Resources: []Resource{
+				{ResourceOrData: element("azurerm_subnet", attr("name", "String"))},
+			},
+		},
+	}}
+
+	// services can be renamed, elements are matched by name alone
+	to := &Version{Name: "v4.0.0", Services: []Service{
+		{
+			Name: "compute",
+			Resources: []Resource{
+				{ResourceOrData: element("azurerm_virtual_machine", attr("name", "String", required))},
+				{ResourceOrData: element("azurerm_unknown", attr("name", "String", required))},
+			},
+		},
+		{
+			Name: "networking",
+			Resources: []Resource{
+				{ResourceOrData: element("azurerm_subnet", attr("name", "String", forceNew))},
+			},
+		},
+	}}
+
+	expected := BreakingChanges{
+		From: "v3.0.0",
+		To:   "v4.0.0",
+		Changes: []BreakingChange{
+			{Kind: BreakingResourceRemoved, Name: "azurerm_availability_set"},
+			{Kind: BreakingAttributeNowForceNew, Name: "azurerm_subnet", Attribute: "name"},
+			{Kind: BreakingAttributeNowRequired, Name: "azurerm_virtual_machine", Attribute: "name"},
+			{Kind: BreakingAttributeRemoved, Name: "azurerm_virtual_machine", Attribute: "zone"},
+			{Kind: BreakingDataSourceRemoved, Name: "azurerm_virtual_machine", DataSource: true},
+		},
+	}
+
+	if got := CompareSchemas(from, to); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Client is a field on the service's client.Client struct, ie VMClient *compute.VirtualMachinesClient
+type Client struct {
+	Name   string   // field name
+	Type   string   // ie compute.VirtualMachinesClient
+	Import string   // import path of the type, empty when declared in the client package
+	Sdk    string   // track1, track2, pandora, kermit, giovanni or empty if it isn't from an sdk
+	UsedBy []string // resources and data sources referencing the client
+}
+
+// sdk names as recorded on clients and sdk imports
+const (
+	SdkTrack1   = "track1"
+	SdkTrack2   = "track2"
+	SdkPandora  = "pandora"
+	SdkKermit   = "kermit"
+	SdkGiovanni = "giovanni"
+)
+
+// sdkOfImport returns which sdk an import path belongs to
+func sdkOfImport(path string) string {
+	switch {
+	case strings.HasPrefix(path, importPathPandora):
+		return SdkPandora
+	case strings.HasPrefix(path, importPathAzureSdkGoTrack2):
+		return SdkTrack2
+	case strings.HasPrefix(path, importPathAzureSdkGo):
+		return SdkTrack1
+	case strings.HasPrefix(path, importPathGiovanni):
+		return SdkGiovanni
+	case strings.HasPrefix(path, importPathKermit):
+		return SdkKermit
+	}
+
+	return ""
+}
+
+// ScanClients parses the service's client/client.go and finds which resources and data sources use each client
+func (s *Service) ScanClients() error {
+	bytes, err := fs.ReadFile(s.files, "client/client.go")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading %s/client/client.go: %w", s.Path, err)
+	}
+
+	f, err := parseGoFile("client.go", bytes)
+	if err != nil {
+		s.addDiagnostic("client/client.go", DiagnosticParse, "%v", err)
+		return nil
+	}
+
+	s.Clients = parseClients(f)
+
+	// meta.(*clients.Client).Compute.VMClient or metadata.Client.Compute.VMClient
+	for i := range s.Clients {
+		cl := &s.Clients[i]
+		for _, e := range s.FilterResourcesDatas(func(rds ResourceOrData) bool { return rds.selectors[cl.Name] }) {
+			cl.UsedBy = append(cl.UsedBy, e.Name)
+		}
+	}
+
+	// no longer needed once everything is matched up
+	for i := range s.Resources {
+		s.Resources[i].selectors = nil
+	}
+	for i := range s.DataSources {
+		s.DataSources[i].selectors = nil
+	}
+
+	return nil
+}
+
+// ClientsMigrated is true when every sdk client the service constructs comes from go-azure-sdk
+func (s *Service) ClientsMigrated() bool {
+	for _, cl := range s.Clients {
+		if cl.Sdk != "" && cl.Sdk != SdkPandora {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LegacyClients returns the clients still using a sdk other then go-azure-sdk
+func (s *Service) LegacyClients() []Client {
+	clients := []Client{}
+	for _, cl := range s.Clients {
+		if cl.Sdk != "" && cl.Sdk != SdkPandora {
+			clients = append(clients, cl)
+		}
+	}
+	return clients
+}
+
+func parseClients(f *ast.File) []Client {
+	imports := map[string]string{} // local name -> path
+	for path, name := range fileImports(f) {
+		imports[name] = path
+	}
+
+	clients := []Client{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != "Client" {
+			return true
+		}
+
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		for _, field := range st.Fields.List {
+			t := field.Type
+			if star, ok := t.(*ast.StarExpr); ok {
+				t = star.X
+			}
+
+			cl := Client{}
+			switch t := t.(type) {
+			case *ast.SelectorExpr:
+				pkg, ok := t.X.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				cl.Type = pkg.Name + "." + t.Sel.Name
+				cl.Import = imports[pkg.Name]
+				cl.Sdk = sdkOfImport(cl.Import)
+			case *ast.Ident:
+				cl.Type = t.Name
+			default:
+				continue
+			}
+
+			for _, name := range field.Names {
+				cl.Name = name.Name
+				clients = append(clients, cl)
+			}
+
+			// embedded, ie *compute_2022_03_01.Client
+			if len(field.Names) == 0 {
+				cl.Name = cl.Type[strings.LastIndex(cl.Type, ".")+1:]
+				clients = append(clients, cl)
+			}
+		}
+
+		return false
+	})
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Name < clients[j].Name
+	})
+
+	return clients
+}
+
+// fieldSelectors returns the names selected from other selectors, ie VMClient in meta.Compute.VMClient
+func fieldSelectors(f *ast.File) map[string]bool {
+	selectors := map[string]bool{}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SelectorExpr); ok {
+			if _, ok := s.X.(*ast.SelectorExpr); ok {
+				selectors[s.Sel.Name] = true
+			}
+		}
+		return true
+	})
+
+	return selectors
+}
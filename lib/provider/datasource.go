@@ -2,8 +2,9 @@ package provider
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
 	"regexp"
+	"strings"
 )
 
 type DataSource struct {
@@ -12,33 +13,55 @@ type DataSource struct {
 
 func (ds DataSource) GetTotal() Totals {
 	t := ds.ResourceOrData.GetTotal()
-	t.DataSources++
+	t.Inc(MetricDataSources, 1)
 	return t
 }
 
+var (
+	dataSourceFileRegex = regexp.MustCompile("[a-z_]+_data_source.go$")
+
+	// before v2.40 data sources were named data_source_example.go
+	legacyDataSourceFileRegex = regexp.MustCompile("^data_source_[a-z0-9_]+.go$")
+)
+
+func isDataSourceFile(name string) bool {
+	if strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+
+	return dataSourceFileRegex.MatchString(name) || legacyDataSourceFileRegex.MatchString(name)
+}
+
 func (s *Service) ScanDataSources() error {
-	files, err := os.ReadDir(s.Path)
+	files, err := fs.ReadDir(s.files, ".")
 	if err != nil {
 		return fmt.Errorf("reading %s: %w", s.Path, err)
 	}
 
-	resourceFileRegex := regexp.MustCompile("[a-z_]+_data_source.go$")
-
 	for _, f := range files {
 		name := f.Name()
 
-		if !resourceFileRegex.MatchString(name) {
+		if !isDataSourceFile(name) {
 			continue
 		}
 
-		bytes, err := os.ReadFile(s.Path + "/" + f.Name())
+		if s.exclude(name) {
+			continue
+		}
+
+		bytes, err := fs.ReadFile(s.files, name)
 		if err != nil {
 			return fmt.Errorf("reading %s: %w", f.Name(), err)
 		}
-		content := string(bytes)
+
+		file, err := parseGoFile(name, bytes)
+		if err != nil {
+			s.addDiagnostic(name, DiagnosticParse, "%v", err)
+			continue
+		}
 
 		r := DataSource{
-			ResourceOrData: s.GetResourceOrDataFor(f, content),
+			ResourceOrData: s.GetResourceOrDataFor(f, bytes, file),
 		}
 
 		s.DataSources = append(s.DataSources, r)
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"go/ast"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Detector is a user defined rule run against every resource and data source file, the result is recorded in
+// ResourceOrData.Detected under the rule's name
+type Detector struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+
+	// what to match Pattern against:
+	//   import - import path prefix, ie github.com/tombuildsstuff/kermit/
+	//   regex  - regular expression over the file's source
+	//   call   - function calls as pkg.Func, both can be globs, ie pluginsdk.ImportAsExistsError or *.Get*
+	//   file   - glob of the file name, ie *_legacy_resource.go
+	Type    string `mapstructure:"type"`
+	Pattern string `mapstructure:"pattern"`
+
+	// record the number of matches rather then 1 when there are any
+	Count bool `mapstructure:"count"`
+
+	regex *regexp.Regexp
+}
+
+const (
+	DetectorTypeImport = "import"
+	DetectorTypeRegex  = "regex"
+	DetectorTypeCall   = "call"
+	DetectorTypeFile   = "file"
+)
+
+// Detectors are run in order and keyed by name
+type Detectors []Detector
+
+// Compile validates the detectors and prepares their patterns, names in reserved are taken by something else such as
+// a built in list and can't be used
+func (ds Detectors) Compile(reserved ...string) error {
+	seen := map[string]bool{}
+	taken := map[string]bool{}
+	for _, name := range reserved {
+		taken[name] = true
+	}
+
+	for i := range ds {
+		d := &ds[i]
+
+		if d.Name == "" {
+			return fmt.Errorf("detector %d has no name", i)
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("detector %s is defined more then once", d.Name)
+		}
+		if taken[d.Name] {
+			return fmt.Errorf("detector %s has the same name as a built in list, expected none of %s", d.Name, strings.Join(reserved, ", "))
+		}
+		seen[d.Name] = true
+
+		if d.Pattern == "" {
+			return fmt.Errorf("detector %s has no pattern", d.Name)
+		}
+
+		switch d.Type {
+		case DetectorTypeImport:
+		case DetectorTypeRegex:
+			re, err := regexp.Compile(d.Pattern)
+			if err != nil {
+				return fmt.Errorf("compiling pattern for detector %s: %w", d.Name, err)
+			}
+			d.regex = re
+		case DetectorTypeCall:
+			if !strings.Contains(d.Pattern, ".") {
+				return fmt.Errorf("detector %s call pattern '%s' must be pkg.Func", d.Name, d.Pattern)
+			}
+			fallthrough
+		case DetectorTypeFile:
+			if _, err := path.Match(d.Pattern, ""); err != nil {
+				return fmt.Errorf("detector %s pattern '%s': %w", d.Name, d.Pattern, err)
+			}
+		default:
+			return fmt.Errorf("detector %s has unknown type '%s', expected import, regex, call or file", d.Name, d.Type)
+		}
+	}
+
+	return nil
+}
+
+// Names returns the detector names sorted
+func (ds Detectors) Names() []string {
+	names := make([]string, 0, len(ds))
+	for _, d := range ds {
+		names = append(names, d.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the detector called name
+func (ds Detectors) Get(name string) (Detector, bool) {
+	for _, d := range ds {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Detector{}, false
+}
+
+// Detect runs the detectors against a file, only rules that match are returned
+func (ds Detectors) Detect(fileName string, content []byte, f *ast.File, imports map[string]string) map[string]int {
+	detected := map[string]int{}
+
+	for _, d := range ds {
+		n := d.matches(fileName, content, f, imports)
+		if n == 0 {
+			continue
+		}
+
+		if !d.Count {
+			n = 1
+		}
+		detected[d.Name] = n
+	}
+
+	return detected
+}
+
+func (d Detector) matches(fileName string, content []byte, f *ast.File, imports map[string]string) int {
+	switch d.Type {
+	case DetectorTypeImport:
+		n := 0
+		for p := range imports {
+			if strings.HasPrefix(p, d.Pattern) {
+				n++
+			}
+		}
+		return n
+	case DetectorTypeRegex:
+		return len(d.regex.FindAllIndex(content, -1))
+	case DetectorTypeCall:
+		return countCalls(f, d.Pattern)
+	case DetectorTypeFile:
+		if ok, _ := path.Match(d.Pattern, fileName); ok {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// countCalls counts the calls matching a pkg.Func glob, the receiver can be any identifier ie client.Get
+func countCalls(f *ast.File, pattern string) int {
+	i := strings.LastIndex(pattern, ".")
+	pkgPattern, funcPattern := pattern[:i], pattern[i+1:]
+
+	n := 0
+	ast.Inspect(f, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		s, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		// the last part of the receiver, ie Get in meta.Client.Get()
+		var recv string
+		switch x := s.X.(type) {
+		case *ast.Ident:
+			recv = x.Name
+		case *ast.SelectorExpr:
+			recv = x.Sel.Name
+		default:
+			return true
+		}
+
+		if ok, _ := path.Match(pkgPattern, recv); !ok {
+			return true
+		}
+		if ok, _ := path.Match(funcPattern, s.Sel.Name); ok {
+			n++
+		}
+
+		return true
+	})
+
+	return n
+}
+
+// Fingerprint identifies the set of rules so results scanned with different detectors are not mixed up
+func (ds Detectors) Fingerprint() string {
+	if len(ds) == 0 {
+		return ""
+	}
+
+	rules := make([]string, 0, len(ds))
+	for _, d := range ds {
+		rules = append(rules, fmt.Sprintf("%s|%s|%s|%t", d.Name, d.Type, d.Pattern, d.Count))
+	}
+	sort.Strings(rules)
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(rules, "\n"))))
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectorsCompile(t *testing.T) {
+	reserved := []string{"typed", "untyped"}
+
+	cases := []struct {
+		name      string
+		detectors Detectors
+		err       string // substring of the error, empty when it should compile
+	}{
+		{
+			name: "every type",
+			detectors: Detectors{
+				{Name: "kermit", Type: DetectorTypeImport, Pattern: "github.com/tombuildsstuff/kermit/"},
+				{Name: "todo", Type: DetectorTypeRegex, Pattern: `(?i)todo`},
+				{Name: "gets", Type: DetectorTypeCall, Pattern: "*.Get*"},
+				{Name: "legacy", Type: DetectorTypeFile, Pattern: "*_legacy_resource.go"},
+			},
+		},
+		{
+			name:      "no detectors",
+			detectors: Detectors{},
+		},
+		{
+			name:      "missing name",
+			detectors: Detectors{{Type: DetectorTypeImport, Pattern: "github.com/"}},
+			err:       "detector 0 has no name",
+		},
+		{
+			name: "duplicate name",
+			detectors: Detectors{
+				{Name: "kermit", Type: DetectorTypeImport, Pattern: "github.com/tombuildsstuff/kermit/"},
+				{Name: "kermit", Type: DetectorTypeFile, Pattern: "*.go"},
+			},
+			err: "detector kermit is defined more then once",
+		},
+		{
+			name:      "reserved name",
+			detectors: Detectors{{Name: "typed", Type: DetectorTypeImport, Pattern: "github.com/"}},
+			err:       "detector typed has the same name as a built in list, expected none of typed, untyped",
+		},
+		{
+			name:      "missing pattern",
+			detectors: Detectors{{Name: "empty", Type: DetectorTypeImport}},
+			err:       "detector empty has no pattern",
+		},
+		{
+			name:      "bad regex",
+			detectors: Detectors{{Name: "broken", Type: DetectorTypeRegex, Pattern: "("}},
+			err:       "compiling pattern for detector broken",
+		},
+		{
+			name:      "call without a package",
+			detectors: Detectors{{Name: "gets", Type: DetectorTypeCall, Pattern: "Get"}},
+			err:       "detector gets call pattern 'Get' must be pkg.Func",
+		},
+		{
+			name:      "bad call glob",
+			detectors: Detectors{{Name: "gets", Type: DetectorTypeCall, Pattern: "client.[Get"}},
+			err:       "detector gets pattern 'client.[Get'",
+		},
+		{
+			name:      "bad file glob",
+			detectors: Detectors{{Name: "legacy", Type: DetectorTypeFile, Pattern: "[*.go"}},
+			err:       "detector legacy pattern '[*.go'",
+		},
+		{
+			name:      "unknown type",
+			detectors: Detectors{{Name: "other", Type: "ast", Pattern: "x"}},
+			err:       "detector other has unknown type 'ast'",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.detectors.Compile(reserved...)
+
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDetectorsDetect(t *testing.T) {
+	src := `package compute
+
+import (
+	"github.com/tombuildsstuff/kermit/sdk/compute/2022-08-01/compute"
+)
+
+// TODO: remove once the api is fixed
+func read(meta interface{}) {
+	client := meta.(*clients.Client).Compute.VMClient
+	client.Get(ctx, id)
+	client.GetInstanceView(ctx, id)
+	meta.Client.Get(ctx, id)
+	_ = compute.VirtualMachine{}
+}
+`
+
+	ds := Detectors{
+		{Name: "kermit", Type: DetectorTypeImport, Pattern: "github.com/tombuildsstuff/kermit/"},
+		{Name: "pandora", Type: DetectorTypeImport, Pattern: "github.com/hashicorp/go-azure-sdk/"},
+		{Name: "todo", Type: DetectorTypeRegex, Pattern: `TODO`},
+		{Name: "gets", Type: DetectorTypeCall, Pattern: "*.Get*", Count: true},
+		{Name: "client gets", Type: DetectorTypeCall, Pattern: "Client.Get"},
+		{Name: "resource file", Type: DetectorTypeFile, Pattern: "*_resource.go"},
+		{Name: "legacy file", Type: DetectorTypeFile, Pattern: "*_legacy_resource.go"},
+	}
+	if err := ds.Compile(); err != nil {
+		t.Fatalf("compiling: %v", err)
+	}
+
+	f, err := parseGoFile("thing_resource.go", []byte(src))
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	expected := map[string]int{
+		"kermit":        1,
+		"todo":          1,
+		"gets":          3,
+		"client gets":   1,
+		"resource file": 1,
+	}
+
+	got := ds.Detect("thing_resource.go", []byte(src), f, fileImports(f))
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
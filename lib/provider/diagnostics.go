@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Diagnostic is a problem found while scanning a file that was skipped over rather then failing the scan
+type Diagnostic struct {
+	File    string // path of the file, ie /path/to/repo/internal/services/compute/example_resource.go
+	Rule    string // short name of the check, ie create-missing
+	Message string
+}
+
+// rules diagnostics are recorded under
+const (
+	DiagnosticParse          = "parse"
+	DiagnosticCreateMissing  = "create-missing"
+	DiagnosticCreateMultiple = "create-multiple"
+	DiagnosticUpdateMultiple = "update-multiple"
+)
+
+func (s *Service) addDiagnostic(fileName, rule, format string, a ...interface{}) {
+	s.Diagnostics = append(s.Diagnostics, Diagnostic{
+		File:    s.Path + "/" + fileName,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// Diagnostics returns every service's diagnostics
+func (v *Version) Diagnostics() []Diagnostic {
+	diags := []Diagnostic{}
+	for _, s := range v.Services {
+		diags = append(diags, s.Diagnostics...)
+	}
+	return diags
+}
+
+// DiagnosticsByRule groups diagnostics by rule, returning the rules sorted
+func DiagnosticsByRule(diags []Diagnostic) ([]string, map[string][]Diagnostic) {
+	byRule := map[string][]Diagnostic{}
+	for _, d := range diags {
+		byRule[d.Rule] = append(byRule[d.Rule], d)
+	}
+
+	rules := make([]string, 0, len(byRule))
+	for r := range byRule {
+		rules = append(rules, r)
+	}
+	sort.Strings(rules)
+
+	return rules, byRule
+}
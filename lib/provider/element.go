@@ -1,100 +1,150 @@
 package provider
 
 import (
+	"go/ast"
+	"go/token"
 	"io/fs"
-	"regexp"
-	"strings"
+	"path"
 )
 
 // todo this is a TERRIBLE name, figure something better out.
 type ResourceOrData struct {
 	Name       string
-	Service    *Service
+	Service    *Service `json:"-"`
 	GoPath     string
 	GoFileName string
 	TestPaths  []string
+	AccTests   AccTests
+	Schema     *Schema // nil unless the version was scanned with ScanSchemas or it could not be found
 
 	IsTyped     bool
 	IsGenerated bool
 
-	SdkAzureSdkGo bool
-	SdkKermit     bool
-	SdkPandora    bool
-	SdkGiovanni   bool
+	SdkAzureSdkGo       bool // track1, services/...
+	SdkAzureSdkGoTrack2 bool // track2, sdk/resourcemanager/... & sdk/azcore
+	SdkKermit           bool
+	SdkPandora          bool
+	SdkGiovanni         bool
 
+	SdkImports []SdkImport // versioned api packages imported
+
+	// imports a parse package, the service's own or another's, whether or not a parse function is called. before
+	// files were parsed it meant calling parse.<Func>, so files only using parse types are now counted too
 	UsesBuiltInParse bool
+	BuiltInParsers   []string // functions called on the parse package, ie VirtualMachineID
+
+	IsRegistered bool // found in the service's registration.go
+
+	Detected map[string]int // results of the configured detectors that matched, 1 unless the detector counts
 
 	// nwe base layer
+
+	decls     declarations
+	selectors map[string]bool // used to find which clients are used
 }
 
-func (s *Service) GetResourceOrDataFor(file fs.DirEntry, content string) ResourceOrData {
-	fileName := file.Name()
+const (
+	importPathAzureSdkGo       = "github.com/Azure/azure-sdk-for-go/"
+	importPathAzureSdkGoTrack2 = "github.com/Azure/azure-sdk-for-go/sdk/"
+	importPathPandora          = "github.com/hashicorp/go-azure-sdk/"
+	importPathGiovanni         = "github.com/tombuildsstuff/giovanni/"
+	importPathKermit           = "github.com/tombuildsstuff/kermit/"
+)
 
-	name := "azurerm_" + strings.ReplaceAll(fileName, "_data_source.go", "")
-	name = "azurerm_" + strings.ReplaceAll(name, "_resource.go", "")
+func (s *Service) GetResourceOrDataFor(file fs.DirEntry, content []byte, f *ast.File) ResourceOrData {
+	fileName := file.Name()
 
+	// replaced with the real name when found in registration.go
 	e := ResourceOrData{
-		Name:       name,
+		Name:       nameFromFileName(fileName),
 		Service:    s,
 		GoPath:     s.Path + "/" + fileName,
 		GoFileName: fileName,
-		// TestsPath: s.Path + "/" + strings.Replace(name, "_resource.go", "_resource_test.go", -1),
-	}
-
-	// todo there is a better way to do this
-	// map regex -> *property to flip
-
-	// is typed
-	isTypedHastfSchema := regexp.MustCompile("`tfschema:\"[a-zA-Z_]*\"`")
-	if isTypedHastfSchema.MatchString(content) {
-		e.IsTyped = true
-	}
-
-	isTypedResourceRegex := regexp.MustCompile("_ sdk.Resource[a-zA-Z]* += ")
-	if isTypedResourceRegex.MatchString(content) {
-		e.IsTyped = true
-	}
-
-	isTypedDataSourceRegex := regexp.MustCompile("_ sdk.DataSource[a-zA-Z]* += ")
-	if isTypedDataSourceRegex.MatchString(content) {
-		e.IsTyped = true
 	}
 
-	// is using pandora SDK
-	isPandoraRegex := regexp.MustCompile("\"github.com/hashicorp/go-azure-sdk/")
-	if isPandoraRegex.MatchString(content) {
-		e.SdkPandora = true
+	imports := fileImports(f)
+
+	// sdks in use, go won't compile with unused imports so an import means it is used
+	for path := range imports {
+		switch sdkOfImport(path) {
+		case SdkPandora:
+			e.SdkPandora = true
+		case SdkTrack2:
+			e.SdkAzureSdkGoTrack2 = true
+		case SdkTrack1:
+			e.SdkAzureSdkGo = true
+		case SdkGiovanni:
+			e.SdkGiovanni = true
+		case SdkKermit:
+			e.SdkKermit = true
+		}
 	}
 
-	// is using track 1 sdk
-	isTrack1Regex := regexp.MustCompile("\"github.com/Azure/azure-sdk-for-go/")
-	if isTrack1Regex.MatchString(content) {
-		e.SdkAzureSdkGo = true
-	}
+	e.SdkImports = sdkImportsOf(imports)
 
-	// is using giovanni sdk
-	isGiovanniRegex := regexp.MustCompile("\"github.com/tombuildsstuff/giovanni/")
-	if isGiovanniRegex.MatchString(content) {
-		e.SdkGiovanni = true
+	// uses built in parse (ie the service's own parse package rather then the pandora parse functions), preferring
+	// the service's own when another service's is imported too
+	if parseName, ok := importNameWithSuffix(f, "/"+path.Base(s.Path)+"/parse", "/parse"); ok {
+		e.UsesBuiltInParse = true
+		e.BuiltInParsers = calledFuncs(f, parseName)
 	}
 
-	// is using kermit  sdk
-	isKermitRegex := regexp.MustCompile("\"github.com/tombuildsstuff/kermit/")
-	if isKermitRegex.MatchString(content) {
-		e.SdkKermit = true
-	}
+	// is typed: asserts it implements sdk.Resource/sdk.DataSource or has a tfschema model
+	e.IsTyped = isTyped(f)
 
 	// is autogenerated
-	isGeneratedRegex := regexp.MustCompile("// NOTE: this file is generated")
-	if isGeneratedRegex.MatchString(content) {
+	if hasCommentContaining(f, "NOTE: this file is generated") {
 		e.IsGenerated = true
 	}
 
-	// uses built in parse (ie not pandora parse function)
-	isBuiltInParse := regexp.MustCompile("= parse.")
-	if isBuiltInParse.MatchString(content) {
-		e.UsesBuiltInParse = true
+	e.Detected = s.detectors.Detect(fileName, content, f, imports)
+
+	if s.scanSchemas {
+		e.Schema = extractSchema(f, e.IsTyped)
 	}
 
+	e.decls = fileDeclarations(f)
+	e.selectors = fieldSelectors(f)
+
 	return e
 }
+
+func isTyped(f *ast.File) bool {
+	typed := false
+
+	sdkName, importsSdk := importNameWithSuffix(f, "/internal/sdk")
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if typed {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			if n.Tok != token.VAR || !importsSdk {
+				return true
+			}
+
+			// var _ sdk.Resource = ExampleResource{}
+			for _, spec := range n.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok || len(vs.Names) == 0 || vs.Names[0].Name != "_" {
+					continue
+				}
+
+				if isSelector(vs.Type, sdkName, "Resource") || isSelector(vs.Type, sdkName, "DataSource") {
+					typed = true
+				}
+			}
+		case *ast.Field:
+			// Name string `tfschema:"name"`
+			if _, ok := structTagValue(n.Tag, "tfschema"); ok {
+				typed = true
+			}
+		}
+
+		return true
+	})
+
+	return typed
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// elementFor classifies src as if it were fileName in the compute service
+func elementFor(t *testing.T, fileName, src string) ResourceOrData {
+	t.Helper()
+
+	files := fstest.MapFS{fileName: &fstest.MapFile{Data: []byte(src)}}
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	f, err := parseGoFile(fileName, []byte(src))
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	s := &Service{Name: "compute", Path: "/provider/internal/services/compute", files: files}
+	return s.GetResourceOrDataFor(entries[0], []byte(src), f)
+}
+
+func TestGetResourceOrDataForClassification(t *testing.T) {
+	type flags struct {
+		typed, generated, builtInParse            bool
+		pandora, track1, track2, kermit, giovanni bool
+	}
+
+	cases := []struct {
+		name     string
+		src      string
+		expected flags
+		parsers  []string
+	}{
+		{
+			name: "typed by assertion",
+			src: `package compute
+
+import (
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/sshpublickeys"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+var _ sdk.ResourceWithUpdate = SshKeyResource{}
+
+type SshKeyResource struct{}
+
+var _ = sshpublickeys.SshPublicKeyId{}
+`,
+			expected: flags{typed: true, pandora: true},
+		},
+		{
+			name: "typed by assertion with an aliased sdk",
+			src: `package compute
+
+import (
+	tfsdk "github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+var _ tfsdk.DataSource = ThingDataSource{}
+
+type ThingDataSource struct{}
+`,
+			expected: flags{typed: true},
+		},
+		{
+			name: "typed by a tfschema struct tag",
+			src: `package compute
+
+type ThingModel struct {
+	Name string ` + "`tfschema:\"name\"`" + `
+}
+`,
+			expected: flags{typed: true},
+		},
+		{
+			name: "a named sdk.Resource var is not an assertion",
+			src: `package compute
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+var thing sdk.Resource = nil
+`,
+		},
+		{
+			name: "sdk named selector without importing internal/sdk is untyped",
+			src: `package compute
+
+import (
+	sdk "github.com/example/other/sdk"
+)
+
+var _ sdk.Resource = ThingResource{}
+
+type ThingResource struct{}
+`,
+		},
+		{
+			name: "commented out imports and strings are ignored",
+			src: `package compute
+
+import (
+	// "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"fmt"
+)
+
+/*
+import "github.com/tombuildsstuff/kermit/sdk/compute/2022-08-01/compute"
+var _ sdk.Resource = ThingResource{}
+*/
+
+var usage = "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager and tfschema:\"name\" and var _ sdk.Resource = x"
+
+type ThingModel struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+var _ = fmt.Sprintf
+`,
+		},
+		{
+			name: "every sdk",
+			src: `package compute
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/virtualmachines"
+	"github.com/tombuildsstuff/giovanni/storage/2020-08-04/blob/blobs"
+	kcompute "github.com/tombuildsstuff/kermit/sdk/compute/2022-08-01/compute"
+)
+
+var _, _, _, _, _ = compute.VirtualMachine{}, armcompute.VirtualMachine{}, virtualmachines.VirtualMachine{}, blobs.Client{}, kcompute.Client{}
+`,
+			expected: flags{pandora: true, track1: true, track2: true, kermit: true, giovanni: true},
+		},
+		{
+			name: "generated marker comment",
+			src: `package compute
+
+// NOTE: this file is generated - manual changes will be overwritten.
+
+type ThingResource struct{}
+`,
+			expected: flags{generated: true},
+		},
+		{
+			name: "generated marker in a string is not generated",
+			src: `package compute
+
+var note = "// NOTE: this file is generated"
+`,
+		},
+		{
+			name: "parse functions called",
+			src: `package compute
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/parse"
+)
+
+func read() {
+	parse.VirtualMachineID("")
+	parse.AvailabilitySetID("")
+	parse.VirtualMachineID("")
+}
+`,
+			expected: flags{builtInParse: true},
+			parsers:  []string{"AvailabilitySetID", "VirtualMachineID"},
+		},
+		{
+			name: "parse types only",
+			src: `package compute
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/parse"
+)
+
+var _ parse.VirtualMachineId
+`,
+			expected: flags{builtInParse: true},
+			parsers:  []string{},
+		},
+		{
+			name: "the service's own parse package is preferred",
+			src: `package compute
+
+import (
+	networkParse "github.com/hashicorp/terraform-provider-azurerm/internal/services/network/parse"
+	computeParse "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/parse"
+)
+
+func read() {
+	networkParse.SubnetID("")
+	computeParse.VirtualMachineID("")
+}
+`,
+			expected: flags{builtInParse: true},
+			parsers:  []string{"VirtualMachineID"},
+		},
+		{
+			name: "another service's parse package in source order",
+			src: `package compute
+
+import (
+	networkParse "github.com/hashicorp/terraform-provider-azurerm/internal/services/network/parse"
+	storageParse "github.com/hashicorp/terraform-provider-azurerm/internal/services/storage/parse"
+)
+
+func read() {
+	storageParse.StorageAccountID("")
+	networkParse.SubnetID("")
+}
+`,
+			expected: flags{builtInParse: true},
+			parsers:  []string{"SubnetID"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := elementFor(t, "thing_resource.go", tc.src)
+
+			got := flags{
+				typed:        e.IsTyped,
+				generated:    e.IsGenerated,
+				builtInParse: e.UsesBuiltInParse,
+				pandora:      e.SdkPandora,
+				track1:       e.SdkAzureSdkGo,
+				track2:       e.SdkAzureSdkGoTrack2,
+				kermit:       e.SdkKermit,
+				giovanni:     e.SdkGiovanni,
+			}
+			if got != tc.expected {
+				t.Fatalf("expected %+v, got %+v", tc.expected, got)
+			}
+
+			if tc.parsers != nil && !reflect.DeepEqual(e.BuiltInParsers, tc.parsers) {
+				t.Fatalf("expected parsers %v, got %v", tc.parsers, e.BuiltInParsers)
+			}
+
+			if e.Name != "azurerm_thing" || e.GoFileName != "thing_resource.go" {
+				t.Fatalf("expected azurerm_thing from thing_resource.go, got %s from %s", e.Name, e.GoFileName)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Exclusion skips resource and data source files that look like one but aren't, ie shared base files
+type Exclusion struct {
+	// glob of the file name, ie *_base_resource.go
+	Pattern string `mapstructure:"pattern"`
+
+	// go-version constraint of the provider versions it applies to, ie "< 3.0.0", empty for all
+	Versions string `mapstructure:"versions"`
+
+	Reason string `mapstructure:"reason"`
+
+	constraints version.Constraints
+}
+
+// Exclusions are checked in order, the first match wins. ones with versions must be compiled
+type Exclusions []Exclusion
+
+// DefaultExclusions are used unless the config has its own
+var DefaultExclusions = Exclusions{
+	{Pattern: "bot_service_base_resource.go", Reason: "shared base of the bot service resources"},
+	{Pattern: "export_base_resource.go", Reason: "shared base of the cost management export resources"},
+	{Pattern: "assignment_base_resource.go", Reason: "shared base of the policy assignment resources"},
+	{Pattern: "container_registry_migrate_resource.go", Reason: "schema migration"},
+	{Pattern: "resource_group_data_source_resource.go", Reason: "helper for the resource group data source"},
+	{Pattern: "*migration_resource.go", Reason: "older schema migration"},
+	{Pattern: "*migration_test_resource.go", Reason: "older schema migration"},
+}
+
+// ExcludedFile is a file that was skipped and the exclusion that skipped it
+type ExcludedFile struct {
+	File    string
+	Pattern string
+	Reason  string
+}
+
+// unreleased versions such as main are newer then every release
+var unreleased = version.Must(version.NewVersion("999999.0.0"))
+
+// Compile validates the exclusions and parses their version constraints
+func (es Exclusions) Compile() error {
+	for i := range es {
+		e := &es[i]
+
+		if e.Pattern == "" {
+			return fmt.Errorf("exclusion %d has no pattern", i)
+		}
+		if _, err := path.Match(e.Pattern, ""); err != nil {
+			return fmt.Errorf("exclusion pattern '%s': %w", e.Pattern, err)
+		}
+
+		if e.Versions != "" {
+			cs, err := version.NewConstraint(e.Versions)
+			if err != nil {
+				return fmt.Errorf("exclusion %s versions '%s': %w", e.Pattern, e.Versions, err)
+			}
+			e.constraints = cs
+		}
+	}
+
+	return nil
+}
+
+// Match returns the first exclusion that applies to fileName in the provider version named ver
+func (es Exclusions) Match(fileName, ver string) (Exclusion, bool) {
+	v, err := version.NewVersion(ver)
+	if err != nil {
+		v = unreleased
+	}
+
+	for _, e := range es {
+		if ok, _ := path.Match(e.Pattern, fileName); !ok {
+			continue
+		}
+
+		if e.constraints != nil && !e.constraints.Check(v) {
+			continue
+		}
+
+		return e, true
+	}
+
+	return Exclusion{}, false
+}
+
+// Fingerprint identifies the exclusions so results scanned with different ones are not mixed up, unlike detectors
+// order matters as it decides which reason is recorded
+func (es Exclusions) Fingerprint() string {
+	if len(es) == 0 {
+		return ""
+	}
+
+	rules := make([]string, 0, len(es))
+	for _, e := range es {
+		rules = append(rules, fmt.Sprintf("%s|%s|%s", e.Pattern, e.Versions, e.Reason))
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(rules, "\n"))))
+}
+
+// exclude checks fileName against the service's exclusions and records it when skipped
+func (s *Service) exclude(fileName string) bool {
+	e, ok := s.exclusions.Match(fileName, s.version)
+	if !ok {
+		return false
+	}
+
+	s.Excluded = append(s.Excluded, ExcludedFile{
+		File:    fileName,
+		Pattern: e.Pattern,
+		Reason:  e.Reason,
+	})
+
+	return true
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExclusionsMatch(t *testing.T) {
+	es := Exclusions{
+		{Pattern: "bot_service_base_resource.go", Reason: "base"},
+		{Pattern: "legacy_*_resource.go", Versions: "< 3.0.0", Reason: "removed in 3.0"},
+		{Pattern: "*migration_resource.go", Versions: ">= 2.50.0, < 4.0.0", Reason: "migration"},
+		{Pattern: "*_resource.go", Versions: ">= 4.0.0", Reason: "everything in 4.0"},
+		{Pattern: "*migration_resource.go", Reason: "any other migration"},
+	}
+	if err := es.Compile(); err != nil {
+		t.Fatalf("compiling: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		file     string
+		version  string
+		expected string // reason of the matching exclusion, empty for none
+	}{
+		{name: "exact name", file: "bot_service_base_resource.go", version: "v3.0.0", expected: "base"},
+		{name: "not matched", file: "virtual_machine_resource.go", version: "v3.0.0"},
+		{name: "glob is the whole name", file: "a_bot_service_base_resource.go", version: "v3.0.0"},
+		{name: "within the version constraint", file: "legacy_vm_resource.go", version: "v2.99.0", expected: "removed in 3.0"},
+		{name: "outside the version constraint", file: "legacy_vm_resource.go", version: "v3.0.0"},
+		{name: "first match wins", file: "vm_migration_resource.go", version: "v3.1.0", expected: "migration"},
+		{name: "falls through to a later match", file: "vm_migration_resource.go", version: "v2.10.0", expected: "any other migration"},
+		{name: "prerelease is before the release", file: "vm_resource.go", version: "v4.0.0-beta1"},
+		{name: "unparseable version is unreleased", file: "vm_resource.go", version: "main", expected: "everything in 4.0"},
+		{name: "empty version is unreleased", file: "legacy_vm_resource.go", version: "", expected: "everything in 4.0"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, ok := es.Match(tc.file, tc.version)
+
+			if ok != (tc.expected != "") || e.Reason != tc.expected {
+				t.Fatalf("expected %q, got %q (matched %t)", tc.expected, e.Reason, ok)
+			}
+		})
+	}
+}
+
+func TestExclusionsCompile(t *testing.T) {
+	cases := []struct {
+		name       string
+		exclusions Exclusions
+		err        string // substring of the error, empty when it should compile
+	}{
+		{
+			name:       "defaults",
+			exclusions: DefaultExclusions,
+		},
+		{
+			name:       "with versions",
+			exclusions: Exclusions{{Pattern: "*_resource.go", Versions: ">= 2.0.0, < 3.0.0"}},
+		},
+		{
+			name:       "missing pattern",
+			exclusions: Exclusions{{Pattern: "*_resource.go"}, {Reason: "nothing"}},
+			err:        "exclusion 1 has no pattern",
+		},
+		{
+			name:       "bad glob",
+			exclusions: Exclusions{{Pattern: "[*_resource.go"}},
+			err:        "exclusion pattern '[*_resource.go'",
+		},
+		{
+			name:       "bad versions",
+			exclusions: Exclusions{{Pattern: "*_resource.go", Versions: "before 3.0"}},
+			err:        "exclusion *_resource.go versions 'before 3.0'",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// compiled on a copy so the defaults aren't modified
+			es := append(Exclusions{}, tc.exclusions...)
+			err := es.Compile()
+
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestExclusionsFingerprint(t *testing.T) {
+	a := Exclusion{Pattern: "a_resource.go", Reason: "a"}
+	b := Exclusion{Pattern: "b_resource.go", Versions: "< 3.0.0", Reason: "b"}
+
+	if fp := (Exclusions{}).Fingerprint(); fp != "" {
+		t.Fatalf("expected no fingerprint without exclusions, got %s", fp)
+	}
+
+	ab := Exclusions{a, b}.Fingerprint()
+	if ab == "" || ab != (Exclusions{a, b}).Fingerprint() {
+		t.Fatalf("expected a stable fingerprint, got %s", ab)
+	}
+
+	// order decides which reason is recorded so it changes the fingerprint
+	if ab == (Exclusions{b, a}).Fingerprint() {
+		t.Fatalf("expected the order to change the fingerprint")
+	}
+
+	changed := b
+	changed.Reason = "other"
+	if ab == (Exclusions{a, changed}).Fingerprint() {
+		t.Fatalf("expected the reason to change the fingerprint")
+	}
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// treeFS is a read only fs.FS over a git tree object so versions can be scanned without touching the worktree
+type treeFS struct {
+	root *object.Tree
+	time time.Time // commit time, used for file mod times
+
+	// go-git's object storage isn't safe for concurrent reads, parsing is where the time goes anyway
+	mu *sync.Mutex
+}
+
+var (
+	_ fs.FS         = treeFS{}
+	_ fs.ReadDirFS  = treeFS{}
+	_ fs.ReadFileFS = treeFS{}
+)
+
+func newTreeFS(commit *object.Commit) (*treeFS, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("getting tree for %s: %w", commit.Hash, err)
+	}
+
+	return &treeFS{
+		root: tree,
+		time: commit.Committer.When,
+		mu:   &sync.Mutex{},
+	}, nil
+}
+
+func (t treeFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return &treeDir{info: t.dirInfo("."), fs: t, tree: t.root}, nil
+	}
+
+	t.mu.Lock()
+	e, err := t.root.FindEntry(name)
+	t.mu.Unlock()
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+
+	if e.Mode == filemode.Dir {
+		t.mu.Lock()
+		tree, err := t.root.Tree(name)
+		t.mu.Unlock()
+		if err != nil {
+			return nil, pathError("open", name, err)
+		}
+
+		return &treeDir{info: t.dirInfo(e.Name), fs: t, tree: tree}, nil
+	}
+
+	b, err := t.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &treeFile{
+		info:   treeFileInfo{name: e.Name, size: int64(len(b)), mode: e.Mode, time: t.time},
+		Reader: bytes.NewReader(b),
+	}, nil
+}
+
+func (t treeFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, err := t.root.File(name)
+	if err != nil {
+		return nil, pathError("read", name, err)
+	}
+
+	r, err := f.Reader()
+	if err != nil {
+		return nil, pathError("read", name, err)
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func (t treeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tree := t.root
+	if name != "." {
+		var err error
+		if tree, err = t.root.Tree(name); err != nil {
+			return nil, pathError("readdir", name, err)
+		}
+	}
+
+	return t.entries(tree), nil
+}
+
+// pathError reports go-git's not found errors as fs.ErrNotExist, anything else such as a corrupt object is kept so it
+// isn't mistaken for a missing file
+func pathError(op, name string, err error) error {
+	switch {
+	case errors.Is(err, object.ErrFileNotFound),
+		errors.Is(err, object.ErrDirectoryNotFound),
+		errors.Is(err, object.ErrEntryNotFound),
+		errors.Is(err, plumbing.ErrObjectNotFound):
+		err = fs.ErrNotExist
+	}
+
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+func (t treeFS) entries(tree *object.Tree) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(tree.Entries))
+	for _, e := range tree.Entries {
+		// submodules have no content in this repo
+		if e.Mode == filemode.Submodule {
+			continue
+		}
+
+		info := treeFileInfo{name: e.Name, mode: e.Mode, time: t.time}
+		if e.Mode != filemode.Dir {
+			if size, err := tree.Size(e.Name); err == nil {
+				info.size = size
+			}
+		}
+
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	// match os.ReadDir
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries
+}
+
+func (t treeFS) dirInfo(name string) treeFileInfo {
+	return treeFileInfo{name: name, mode: filemode.Dir, time: t.time}
+}
+
+type treeFileInfo struct {
+	name string
+	size int64
+	mode filemode.FileMode
+	time time.Time
+}
+
+func (i treeFileInfo) Name() string       { return i.name }
+func (i treeFileInfo) Size() int64        { return i.size }
+func (i treeFileInfo) ModTime() time.Time { return i.time }
+func (i treeFileInfo) IsDir() bool        { return i.mode == filemode.Dir }
+func (i treeFileInfo) Sys() interface{}   { return nil }
+
+func (i treeFileInfo) Mode() fs.FileMode {
+	switch i.mode {
+	case filemode.Dir:
+		return fs.ModeDir | 0555
+	case filemode.Symlink:
+		return fs.ModeSymlink | 0444
+	case filemode.Executable:
+		return 0555
+	default:
+		return 0444
+	}
+}
+
+type treeFile struct {
+	info treeFileInfo
+	*bytes.Reader
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Close() error               { return nil }
+
+type treeDir struct {
+	info    treeFileInfo
+	fs      treeFS
+	tree    *object.Tree
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read(_ []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.entries == nil {
+		d.fs.mu.Lock()
+		d.entries = d.fs.entries(d.tree)
+		d.fs.mu.Unlock()
+	}
+
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+
+	return remaining[:n], nil
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// commitTreeFS commits files to an in memory repo and returns the commit's tree
+func commitTreeFS(t *testing.T, files map[string]string) *treeFS {
+	t.Helper()
+
+	wt := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), wt)
+	if err != nil {
+		t.Fatalf("initialising repo: %v", err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("getting worktree: %v", err)
+	}
+
+	for name, content := range files {
+		if err := util.WriteFile(wt, name, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+		if _, err := w.Add(name); err != nil {
+			t.Fatalf("adding %s: %v", name, err)
+		}
+	}
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	hash, err := w.Commit("fixture", &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatalf("committing: %v", err)
+	}
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		t.Fatalf("reading commit: %v", err)
+	}
+
+	tfs, err := newTreeFS(commit)
+	if err != nil {
+		t.Fatalf("reading tree: %v", err)
+	}
+
+	return tfs
+}
+
+func TestTreeFS(t *testing.T) {
+	tfs := commitTreeFS(t, map[string]string{
+		"main.go": "package main\n",
+		"internal/services/compute/registration.go":             "package compute\n",
+		"internal/services/compute/virtual_machine_resource.go": "package compute\n\n// vm\n",
+		"internal/services/network/registration.go":             "package network\n",
+	})
+
+	if err := fstest.TestFS(tfs,
+		"main.go",
+		"internal/services/compute/registration.go",
+		"internal/services/compute/virtual_machine_resource.go",
+		"internal/services/network/registration.go",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := fs.ReadFile(tfs, "internal/services/compute/virtual_machine_resource.go")
+	if err != nil {
+		t.Fatalf("reading file: %v", err)
+	}
+	if string(b) != "package compute\n\n// vm\n" {
+		t.Fatalf("expected the committed content, got %q", b)
+	}
+
+	info, err := fs.Stat(tfs, "main.go")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if !info.ModTime().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the commit time, got %v", info.ModTime())
+	}
+}
+
+func TestTreeFSNotExist(t *testing.T) {
+	tfs := commitTreeFS(t, map[string]string{
+		"internal/services/compute/registration.go": "package compute\n",
+	})
+
+	cases := []struct {
+		name string
+		op   func() error
+	}{
+		{
+			name: "open a missing file",
+			op: func() error {
+				_, err := tfs.Open("internal/services/compute/missing.go")
+				return err
+			},
+		},
+		{
+			name: "open under a missing directory",
+			op: func() error {
+				_, err := tfs.Open("internal/services/network/registration.go")
+				return err
+			},
+		},
+		{
+			name: "read a missing file",
+			op: func() error {
+				_, err := tfs.ReadFile("internal/services/compute/missing.go")
+				return err
+			},
+		},
+		{
+			name: "read a missing directory",
+			op: func() error {
+				_, err := tfs.ReadDir("internal/services/network")
+				return err
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.op()
+
+			var pe *fs.PathError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a *fs.PathError, got %v", err)
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Fatalf("expected fs.ErrNotExist, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPathError(t *testing.T) {
+	corrupt := errors.New("corrupt object")
+
+	cases := []struct {
+		name     string
+		err      error
+		notExist bool
+	}{
+		{name: "file not found", err: object.ErrFileNotFound, notExist: true},
+		{name: "directory not found", err: object.ErrDirectoryNotFound, notExist: true},
+		{name: "entry not found", err: object.ErrEntryNotFound, notExist: true},
+		{name: "object not found", err: plumbing.ErrObjectNotFound, notExist: true},
+		{name: "wrapped not found", err: fmt.Errorf("reading tree: %w", object.ErrFileNotFound), notExist: true},
+		{name: "other errors are kept", err: corrupt},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := pathError("open", "thing.go", tc.err)
+
+			if errors.Is(err, fs.ErrNotExist) != tc.notExist {
+				t.Fatalf("expected not exist %t, got %v", tc.notExist, err)
+			}
+			if !tc.notExist && !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v to be kept, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+// MigrationState is how far a resource, data source or service is through the migration to go-azure-sdk
+type MigrationState int
+
+const (
+	MigrationNotApplicable MigrationState = iota // uses no sdk that needs migrating, ie only giovanni or none at all
+	MigrationNotStarted                          // only legacy sdks (track1, track2 or kermit)
+	MigrationPartial                             // go-azure-sdk and a legacy sdk
+	MigrationDone                                // only go-azure-sdk
+)
+
+func (m MigrationState) String() string {
+	switch m {
+	case MigrationNotStarted:
+		return "not-started"
+	case MigrationPartial:
+		return "partial"
+	case MigrationDone:
+		return "done"
+	default:
+		return "not-applicable"
+	}
+}
+
+// Metric is the totals metric elements in this state are counted under, spelt like the other metrics rather then
+// String which is for display and json
+func (m MigrationState) Metric() string {
+	switch m {
+	case MigrationNotStarted:
+		return "migration_not_started"
+	case MigrationPartial:
+		return "migration_partial"
+	case MigrationDone:
+		return "migration_done"
+	default:
+		return "migration_not_applicable"
+	}
+}
+
+// UsesLegacySdk is true when the element imports an sdk that is being replaced by go-azure-sdk
+func (rds ResourceOrData) UsesLegacySdk() bool {
+	return rds.SdkAzureSdkGo || rds.SdkAzureSdkGoTrack2 || rds.SdkKermit
+}
+
+func (rds ResourceOrData) MigrationState() MigrationState {
+	legacy := rds.UsesLegacySdk()
+
+	switch {
+	case rds.SdkPandora && legacy:
+		return MigrationPartial
+	case rds.SdkPandora:
+		return MigrationDone
+	case legacy:
+		return MigrationNotStarted
+	default:
+		return MigrationNotApplicable
+	}
+}
+
+// MigrationState of a service is done or not started only when every element it applies to is
+func (s *Service) MigrationState() MigrationState {
+	return s.CalculateTotals().MigrationState()
+}
+
+// MigrationState rolls up the element states counted in the totals
+func (t Totals) MigrationState() MigrationState {
+	done, partial, notStarted := t.MigrationDone(), t.MigrationPartial(), t.MigrationNotStarted()
+
+	switch {
+	case done+partial+notStarted == 0:
+		return MigrationNotApplicable
+	case partial == 0 && notStarted == 0:
+		return MigrationDone
+	case done == 0 && partial == 0:
+		return MigrationNotStarted
+	default:
+		return MigrationPartial
+	}
+}
+
+func (t Totals) MigrationDone() int          { return t[MigrationDone.Metric()] }
+func (t Totals) MigrationPartial() int       { return t[MigrationPartial.Metric()] }
+func (t Totals) MigrationNotStarted() int    { return t[MigrationNotStarted.Metric()] }
+func (t Totals) MigrationNotApplicable() int { return t[MigrationNotApplicable.Metric()] }
+
+// MigrationApplicable is the number of elements that need or needed migrating
+func (t Totals) MigrationApplicable() int {
+	return t.MigrationDone() + t.MigrationPartial() + t.MigrationNotStarted()
+}
+
+// MigrationRemaining is the number of elements still using a legacy sdk
+func (t Totals) MigrationRemaining() int {
+	return t.MigrationPartial() + t.MigrationNotStarted()
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestResourceOrDataMigrationState(t *testing.T) {
+	cases := []struct {
+		name     string
+		rds      ResourceOrData
+		expected MigrationState
+	}{
+		{
+			name:     "none",
+			rds:      ResourceOrData{},
+			expected: MigrationNotApplicable,
+		},
+		{
+			name:     "giovanni only",
+			rds:      ResourceOrData{SdkGiovanni: true},
+			expected: MigrationNotApplicable,
+		},
+		{
+			name:     "pandora only",
+			rds:      ResourceOrData{SdkPandora: true},
+			expected: MigrationDone,
+		},
+		{
+			name:     "pandora and giovanni",
+			rds:      ResourceOrData{SdkPandora: true, SdkGiovanni: true},
+			expected: MigrationDone,
+		},
+		{
+			name:     "pandora and track1",
+			rds:      ResourceOrData{SdkPandora: true, SdkAzureSdkGo: true},
+			expected: MigrationPartial,
+		},
+		{
+			name:     "pandora and track2",
+			rds:      ResourceOrData{SdkPandora: true, SdkAzureSdkGoTrack2: true},
+			expected: MigrationPartial,
+		},
+		{
+			name:     "pandora and kermit",
+			rds:      ResourceOrData{SdkPandora: true, SdkKermit: true},
+			expected: MigrationPartial,
+		},
+		{
+			name:     "pandora and every legacy sdk",
+			rds:      ResourceOrData{SdkPandora: true, SdkAzureSdkGo: true, SdkAzureSdkGoTrack2: true, SdkKermit: true},
+			expected: MigrationPartial,
+		},
+		{
+			name:     "track1 only",
+			rds:      ResourceOrData{SdkAzureSdkGo: true},
+			expected: MigrationNotStarted,
+		},
+		{
+			name:     "track2 only",
+			rds:      ResourceOrData{SdkAzureSdkGoTrack2: true},
+			expected: MigrationNotStarted,
+		},
+		{
+			name:     "kermit only",
+			rds:      ResourceOrData{SdkKermit: true},
+			expected: MigrationNotStarted,
+		},
+		{
+			name:     "track1 and track2",
+			rds:      ResourceOrData{SdkAzureSdkGo: true, SdkAzureSdkGoTrack2: true},
+			expected: MigrationNotStarted,
+		},
+		{
+			name:     "track1 and giovanni",
+			rds:      ResourceOrData{SdkAzureSdkGo: true, SdkGiovanni: true},
+			expected: MigrationNotStarted,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.rds.MigrationState(); got != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, got)
+			}
+
+			legacy := tc.rds.SdkAzureSdkGo || tc.rds.SdkAzureSdkGoTrack2 || tc.rds.SdkKermit
+			if got := tc.rds.UsesLegacySdk(); got != legacy {
+				t.Fatalf("expected UsesLegacySdk %t, got %t", legacy, got)
+			}
+
+			// an element is counted once however many legacy sdks it uses
+			expected := 0
+			if legacy {
+				expected = 1
+			}
+			if got := tc.rds.GetTotal().SdkLegacy(); got != expected {
+				t.Fatalf("expected %d using a legacy sdk, got %d", expected, got)
+			}
+		})
+	}
+}
+
+func TestServiceMigrationState(t *testing.T) {
+	var (
+		done          = ResourceOrData{SdkPandora: true}
+		partial       = ResourceOrData{SdkPandora: true, SdkAzureSdkGo: true}
+		notStarted    = ResourceOrData{SdkAzureSdkGoTrack2: true}
+		notApplicable = ResourceOrData{SdkGiovanni: true}
+	)
+
+	cases := []struct {
+		name        string
+		resources   []ResourceOrData
+		dataSources []ResourceOrData
+		expected    MigrationState
+		applicable  int
+		remaining   int
+	}{
+		{
+			name:     "empty",
+			expected: MigrationNotApplicable,
+		},
+		{
+			name:      "not applicable",
+			resources: []ResourceOrData{notApplicable, {}},
+			expected:  MigrationNotApplicable,
+		},
+		{
+			name:        "done",
+			resources:   []ResourceOrData{done, notApplicable},
+			dataSources: []ResourceOrData{done},
+			expected:    MigrationDone,
+			applicable:  2,
+		},
+		{
+			name:        "not started",
+			resources:   []ResourceOrData{notStarted, notApplicable},
+			dataSources: []ResourceOrData{notStarted},
+			expected:    MigrationNotStarted,
+			applicable:  2,
+			remaining:   2,
+		},
+		{
+			name:       "partial element",
+			resources:  []ResourceOrData{done, partial},
+			expected:   MigrationPartial,
+			applicable: 2,
+			remaining:  1,
+		},
+		{
+			name:        "done and not started",
+			resources:   []ResourceOrData{done},
+			dataSources: []ResourceOrData{notStarted},
+			expected:    MigrationPartial,
+			applicable:  2,
+			remaining:   1,
+		},
+		{
+			name:       "only partial",
+			resources:  []ResourceOrData{partial, partial},
+			expected:   MigrationPartial,
+			applicable: 2,
+			remaining:  2,
+		},
+		{
+			name:       "partial and not started",
+			resources:  []ResourceOrData{partial, notStarted, notApplicable},
+			expected:   MigrationPartial,
+			applicable: 2,
+			remaining:  2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Service{Name: tc.name}
+			for _, rds := range tc.resources {
+				s.Resources = append(s.Resources, Resource{ResourceOrData: rds})
+			}
+			for _, rds := range tc.dataSources {
+				s.DataSources = append(s.DataSources, DataSource{ResourceOrData: rds})
+			}
+
+			if got := s.MigrationState(); got != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, got)
+			}
+
+			totals := s.CalculateTotals()
+			if got := totals.MigrationApplicable(); got != tc.applicable {
+				t.Fatalf("expected %d applicable, got %d", tc.applicable, got)
+			}
+			if got := totals.MigrationRemaining(); got != tc.remaining {
+				t.Fatalf("expected %d remaining, got %d", tc.remaining, got)
+			}
+
+			// the version rolls up the same way as the service
+			v := Version{Services: []Service{s}}
+			if got := v.CalculateTotals().MigrationState(); got != tc.expected {
+				t.Fatalf("expected version state %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMigrationStateMetric(t *testing.T) {
+	cases := []struct {
+		state  MigrationState
+		metric string
+		str    string
+	}{
+		{MigrationNotApplicable, "migration_not_applicable", "not-applicable"},
+		{MigrationNotStarted, "migration_not_started", "not-started"},
+		{MigrationPartial, "migration_partial", "partial"},
+		{MigrationDone, "migration_done", "done"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.str, func(t *testing.T) {
+			if got := tc.state.Metric(); got != tc.metric {
+				t.Fatalf("expected metric %s, got %s", tc.metric, got)
+			}
+			if got := tc.state.String(); got != tc.str {
+				t.Fatalf("expected string %s, got %s", tc.str, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// BurndownPoint is how many of something were left at a point in time
+type BurndownPoint struct {
+	Date      time.Time
+	Remaining int
+}
+
+// Projection estimates when a burndown reaches zero. dates are zero when it can't be projected, ie the count
+// isn't going down or there aren't enough dated points, and all From's date when it is already Done
+type Projection struct {
+	From      BurndownPoint // the latest point, projections start from here
+	Done      bool          // the latest point is already at zero
+	Linear    time.Time     // least squares fit over every point
+	Recent    time.Time     // average velocity over the recent window
+	Earliest  time.Time     // recent velocity plus a standard deviation
+	Latest    time.Time     // recent velocity less a standard deviation, zero if that is not going down
+	Velocity  float64       // per day over the recent window
+	Deviation float64       // of the velocity over the recent window
+}
+
+// ProjectionWindow is how many of the latest intervals between points make up the recent velocity
+const ProjectionWindow = 6
+
+const day = 24 * time.Hour
+
+// projections further out then this are treated as never, they'd overflow a time.Duration anyway
+const maxProjectionDays = 100 * 365
+
+// ProjectBurndown fits points by date whatever order they are in, points on the same date keep their order. points
+// without a date are ignored
+func ProjectBurndown(points []BurndownPoint) Projection {
+	dated := []BurndownPoint{}
+	for _, p := range points {
+		if !p.Date.IsZero() {
+			dated = append(dated, p)
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].Date.Before(dated[j].Date)
+	})
+
+	p := Projection{}
+	if len(dated) == 0 {
+		return p
+	}
+	p.From = dated[len(dated)-1]
+
+	if p.From.Remaining == 0 {
+		p.Done = true
+		p.Linear, p.Recent, p.Earliest, p.Latest = p.From.Date, p.From.Date, p.From.Date, p.From.Date
+		return p
+	}
+
+	if len(dated) < 2 {
+		return p
+	}
+
+	p.Linear = projectLinear(dated)
+
+	// velocity of each interval in the window, releases on the same day are skipped as they'd be infinite
+	velocities := []float64{}
+	start := len(dated) - 1 - ProjectionWindow
+	if start < 0 {
+		start = 0
+	}
+	for i := start + 1; i < len(dated); i++ {
+		days := dated[i].Date.Sub(dated[i-1].Date).Hours() / 24
+		if days <= 0 {
+			continue
+		}
+		velocities = append(velocities, float64(dated[i-1].Remaining-dated[i].Remaining)/days)
+	}
+
+	if len(velocities) == 0 {
+		return p
+	}
+
+	for _, v := range velocities {
+		p.Velocity += v
+	}
+	p.Velocity /= float64(len(velocities))
+
+	for _, v := range velocities {
+		p.Deviation += (v - p.Velocity) * (v - p.Velocity)
+	}
+	p.Deviation = math.Sqrt(p.Deviation / float64(len(velocities)))
+
+	p.Recent = p.From.at(p.Velocity)
+	p.Earliest = p.From.at(p.Velocity + p.Deviation)
+	p.Latest = p.From.at(p.Velocity - p.Deviation)
+
+	return p
+}
+
+// at is when the point reaches zero going down velocity a day, zero if it never does
+func (p BurndownPoint) at(velocity float64) time.Time {
+	if velocity <= 0 {
+		return time.Time{}
+	}
+
+	return addDays(p.Date, float64(p.Remaining)/velocity)
+}
+
+func addDays(t time.Time, days float64) time.Time {
+	if days > maxProjectionDays {
+		return time.Time{}
+	}
+
+	return t.Add(time.Duration(days * float64(day)))
+}
+
+// projectLinear fits remaining = a + b*days with least squares and returns where it crosses zero
+func projectLinear(points []BurndownPoint) time.Time {
+	first := points[0].Date
+
+	var sx, sy, sxx, sxy float64
+	for _, p := range points {
+		x := p.Date.Sub(first).Hours() / 24
+		y := float64(p.Remaining)
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+
+	n := float64(len(points))
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return time.Time{}
+	}
+
+	b := (n*sxy - sx*sy) / d
+	a := (sy - b*sx) / n
+	if b >= 0 {
+		return time.Time{}
+	}
+
+	return addDays(first, -a/b)
+}
+
+// Projected reports if any projection could be made, including when it is already Done
+func (p Projection) Projected() bool {
+	return !p.Linear.IsZero() || !p.Recent.IsZero()
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var projectionStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// onDay is the date days after projectionStart
+func onDay(days float64) time.Time {
+	return projectionStart.Add(time.Duration(days * float64(24*time.Hour)))
+}
+
+func TestProjectBurndown(t *testing.T) {
+	never := time.Time{}
+
+	cases := []struct {
+		name      string
+		points    []BurndownPoint
+		from      BurndownPoint
+		done      bool
+		linear    time.Time
+		recent    time.Time
+		earliest  time.Time
+		latest    time.Time
+		velocity  float64
+		deviation float64
+	}{
+		{
+			name: "no points",
+		},
+		{
+			name:   "undated points are ignored",
+			points: []BurndownPoint{{Remaining: 10}, {Remaining: 5}},
+		},
+		{
+			name:   "one dated point",
+			points: []BurndownPoint{{Date: onDay(0), Remaining: 10}, {Remaining: 5}},
+			from:   BurndownPoint{Date: onDay(0), Remaining: 10},
+		},
+		{
+			name:     "already done",
+			points:   []BurndownPoint{{Date: onDay(0), Remaining: 10}, {Date: onDay(10), Remaining: 0}},
+			from:     BurndownPoint{Date: onDay(10)},
+			done:     true,
+			linear:   onDay(10),
+			recent:   onDay(10),
+			earliest: onDay(10),
+			latest:   onDay(10),
+		},
+		{
+			name:     "one point already done",
+			points:   []BurndownPoint{{Date: onDay(5), Remaining: 0}},
+			from:     BurndownPoint{Date: onDay(5)},
+			done:     true,
+			linear:   onDay(5),
+			recent:   onDay(5),
+			earliest: onDay(5),
+			latest:   onDay(5),
+		},
+		{
+			name: "steady",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 100},
+				{Date: onDay(10), Remaining: 90},
+				{Date: onDay(20), Remaining: 80},
+			},
+			from:     BurndownPoint{Date: onDay(20), Remaining: 80},
+			linear:   onDay(100),
+			recent:   onDay(100),
+			earliest: onDay(100),
+			latest:   onDay(100),
+			velocity: 1,
+		},
+		{
+			name: "out of order is sorted by date",
+			points: []BurndownPoint{
+				{Date: onDay(20), Remaining: 80},
+				{Date: onDay(0), Remaining: 100},
+				{Date: onDay(10), Remaining: 90},
+			},
+			from:     BurndownPoint{Date: onDay(20), Remaining: 80},
+			linear:   onDay(100),
+			recent:   onDay(100),
+			earliest: onDay(100),
+			latest:   onDay(100),
+			velocity: 1,
+		},
+		{
+			name: "releases on the same day are skipped",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 100},
+				{Date: onDay(10), Remaining: 90},
+				{Date: onDay(10), Remaining: 80},
+			},
+			from:     BurndownPoint{Date: onDay(10), Remaining: 80},
+			linear:   onDay(100 / 1.5),
+			recent:   onDay(90),
+			earliest: onDay(90),
+			latest:   onDay(90),
+			velocity: 1,
+		},
+		{
+			name: "every release on the same day",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 100},
+				{Date: onDay(0), Remaining: 90},
+			},
+			from: BurndownPoint{Date: onDay(0), Remaining: 90},
+		},
+		{
+			name: "flat",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 50},
+				{Date: onDay(10), Remaining: 50},
+				{Date: onDay(20), Remaining: 50},
+			},
+			from: BurndownPoint{Date: onDay(20), Remaining: 50},
+		},
+		{
+			name: "rising",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 50},
+				{Date: onDay(10), Remaining: 60},
+				{Date: onDay(20), Remaining: 70},
+			},
+			from:     BurndownPoint{Date: onDay(20), Remaining: 70},
+			velocity: -1,
+		},
+		{
+			name: "too far out is never",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 1000001},
+				{Date: onDay(10), Remaining: 1000000},
+			},
+			from:     BurndownPoint{Date: onDay(10), Remaining: 1000000},
+			velocity: 0.1,
+		},
+		{
+			name: "band from the deviation",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 100},
+				{Date: onDay(10), Remaining: 90},
+				{Date: onDay(20), Remaining: 70},
+			},
+			from:      BurndownPoint{Date: onDay(20), Remaining: 70},
+			linear:    onDay(305.0 / 3 / 1.5),
+			recent:    onDay(20 + 70.0/1.5),
+			earliest:  onDay(20 + 70.0/2),
+			latest:    onDay(20 + 70.0/1),
+			velocity:  1.5,
+			deviation: 0.5,
+		},
+		{
+			name: "no latest when the band isn't going down",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 100},
+				{Date: onDay(10), Remaining: 70},
+				{Date: onDay(20), Remaining: 80},
+			},
+			from:      BurndownPoint{Date: onDay(20), Remaining: 80},
+			linear:    onDay(280.0 / 3),
+			recent:    onDay(20 + 80.0/1),
+			earliest:  onDay(20 + 80.0/3),
+			latest:    never,
+			velocity:  1,
+			deviation: 2,
+		},
+		{
+			name: "only the recent window counts towards velocity",
+			points: []BurndownPoint{
+				{Date: onDay(0), Remaining: 1000},
+				{Date: onDay(10), Remaining: 100},
+				{Date: onDay(20), Remaining: 90},
+				{Date: onDay(30), Remaining: 80},
+				{Date: onDay(40), Remaining: 70},
+				{Date: onDay(50), Remaining: 60},
+				{Date: onDay(60), Remaining: 50},
+				{Date: onDay(70), Remaining: 40},
+			},
+			from:     BurndownPoint{Date: onDay(70), Remaining: 40},
+			linear:   onDay(57.12871287),
+			recent:   onDay(110),
+			earliest: onDay(110),
+			latest:   onDay(110),
+			velocity: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := ProjectBurndown(tc.points)
+
+			if !p.From.Date.Equal(tc.from.Date) || p.From.Remaining != tc.from.Remaining {
+				t.Fatalf("expected from %v, got %v", tc.from, p.From)
+			}
+			if p.Done != tc.done {
+				t.Fatalf("expected done %t, got %t", tc.done, p.Done)
+			}
+
+			expectDate(t, "recent", tc.recent, p.Recent)
+			expectDate(t, "earliest", tc.earliest, p.Earliest)
+			expectDate(t, "latest", tc.latest, p.Latest)
+			expectDate(t, "linear", tc.linear, p.Linear)
+
+			if math.Abs(p.Velocity-tc.velocity) > 1e-9 {
+				t.Fatalf("expected velocity %f, got %f", tc.velocity, p.Velocity)
+			}
+			if math.Abs(p.Deviation-tc.deviation) > 1e-9 {
+				t.Fatalf("expected deviation %f, got %f", tc.deviation, p.Deviation)
+			}
+
+			projected := tc.done || !tc.linear.IsZero() || !tc.recent.IsZero()
+			if p.Projected() != projected {
+				t.Fatalf("expected projected %t, got %t", projected, p.Projected())
+			}
+		})
+	}
+}
+
+// expectDate compares to the minute as the projected dates come from float days
+func expectDate(t *testing.T, name string, expected, got time.Time) {
+	t.Helper()
+
+	if expected.IsZero() || got.IsZero() {
+		if expected.IsZero() != got.IsZero() {
+			t.Fatalf("expected %s %v, got %v", name, expected, got)
+		}
+		return
+	}
+
+	if d := got.Sub(expected); d > time.Minute || d < -time.Minute {
+		t.Fatalf("expected %s %v, got %v", name, expected, got)
+	}
+}
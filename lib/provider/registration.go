@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Registration is an entry in a service's registration.go, either in the SupportedResources/SupportedDataSources
+// maps for the plugin sdk or the Resources/DataSources slices for the typed sdk
+type Registration struct {
+	Name       string // terraform type, ie azurerm_resource_group, empty when it could not be determined
+	Target     string // function (untyped) or type (typed) registered
+	Typed      bool
+	DataSource bool
+	GoFileName string // file Target is declared in, empty if there is no matching resource or data source file
+}
+
+// declarations are the top level functions and types a file declares, used to map registrations to files
+type declarations struct {
+	funcs map[string]bool
+	types map[string]string // type -> the value returned by its ResourceType() method
+}
+
+func fileDeclarations(f *ast.File) declarations {
+	d := declarations{
+		funcs: map[string]bool{},
+		types: map[string]string{},
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range decl.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					if _, ok := d.types[ts.Name.Name]; !ok {
+						d.types[ts.Name.Name] = ""
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				d.funcs[decl.Name.Name] = true
+				continue
+			}
+
+			// func (r ExampleResource) ResourceType() string { return "azurerm_example" }
+			if decl.Name.Name != "ResourceType" || decl.Body == nil || len(decl.Recv.List) != 1 {
+				continue
+			}
+
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			ident, ok := recv.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			for _, stmt := range decl.Body.List {
+				ret, ok := stmt.(*ast.ReturnStmt)
+				if !ok || len(ret.Results) != 1 {
+					continue
+				}
+
+				if name, ok := stringLiteral(ret.Results[0]); ok {
+					d.types[ident.Name] = name
+				}
+			}
+		}
+	}
+
+	return d
+}
+
+// ScanRegistrations parses the service's registration.go and names the resources and data sources from it
+func (s *Service) ScanRegistrations() error {
+	bytes, err := fs.ReadFile(s.files, "registration.go")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading %s/registration.go: %w", s.Path, err)
+	}
+
+	f, err := parseGoFile("registration.go", bytes)
+	if err != nil {
+		s.addDiagnostic("registration.go", DiagnosticParse, "%v", err)
+		return nil
+	}
+
+	s.RegistrationGoFileName = "registration.go"
+	s.Registrations = parseRegistrations(f)
+
+	for i, reg := range s.Registrations {
+		var e *ResourceOrData
+		if reg.DataSource {
+			for j := range s.DataSources {
+				if s.DataSources[j].declares(reg) {
+					e = &s.DataSources[j].ResourceOrData
+					break
+				}
+			}
+		} else {
+			for j := range s.Resources {
+				if s.Resources[j].declares(reg) {
+					e = &s.Resources[j].ResourceOrData
+					break
+				}
+			}
+		}
+
+		if e == nil {
+			continue
+		}
+
+		if reg.Typed {
+			reg.Name = e.decls.types[reg.Target]
+		}
+
+		// the first registration wins, the rest are aliases (ie renamed resources)
+		if reg.Name != "" && !e.IsRegistered {
+			e.Name = reg.Name
+		}
+
+		e.IsRegistered = true
+		reg.GoFileName = e.GoFileName
+		s.Registrations[i] = reg
+	}
+
+	// no longer needed once everything is matched up
+	for i := range s.Resources {
+		s.Resources[i].decls = declarations{}
+	}
+	for i := range s.DataSources {
+		s.DataSources[i].decls = declarations{}
+	}
+
+	return nil
+}
+
+// UnregisteredResourcesDatas returns the resources and data sources that do not appear in registration.go
+func (s *Service) UnregisteredResourcesDatas() []ResourceOrData {
+	if s.RegistrationGoFileName == "" {
+		return []ResourceOrData{}
+	}
+
+	return s.FilterResourcesDatas(func(rds ResourceOrData) bool {
+		return !rds.IsRegistered
+	})
+}
+
+// RegistrationsWithoutFile returns the registrations that could not be matched to a resource or data source file
+func (s *Service) RegistrationsWithoutFile() []Registration {
+	regs := []Registration{}
+	for _, r := range s.Registrations {
+		if r.GoFileName == "" {
+			regs = append(regs, r)
+		}
+	}
+	return regs
+}
+
+func (rds ResourceOrData) declares(reg Registration) bool {
+	if reg.Typed {
+		_, ok := rds.decls.types[reg.Target]
+		return ok
+	}
+
+	return rds.decls.funcs[reg.Target]
+}
+
+func parseRegistrations(f *ast.File) []Registration {
+	regs := []Registration{}
+
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || fd.Body == nil {
+			continue
+		}
+
+		switch fd.Name.Name {
+		case "SupportedResources":
+			regs = append(regs, untypedRegistrations(fd.Body, false)...)
+		case "SupportedDataSources":
+			regs = append(regs, untypedRegistrations(fd.Body, true)...)
+		case "Resources":
+			regs = append(regs, typedRegistrations(fd.Body, false)...)
+		case "DataSources":
+			regs = append(regs, typedRegistrations(fd.Body, true)...)
+		}
+	}
+
+	return regs
+}
+
+// untypedRegistrations finds `"azurerm_example": resourceExample(),` and `resources["azurerm_example"] = resourceExample()`
+func untypedRegistrations(body *ast.BlockStmt, dataSource bool) []Registration {
+	regs := []Registration{}
+
+	add := func(key, value ast.Expr) {
+		name, ok := stringLiteral(key)
+		if !ok {
+			return
+		}
+
+		call, ok := value.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+
+		if fn, ok := call.Fun.(*ast.Ident); ok {
+			regs = append(regs, Registration{
+				Name:       name,
+				Target:     fn.Name,
+				DataSource: dataSource,
+			})
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.KeyValueExpr:
+			add(n.Key, n.Value)
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			if index, ok := n.Lhs[0].(*ast.IndexExpr); ok {
+				add(index.Index, n.Rhs[0])
+			}
+		}
+		return true
+	})
+
+	return regs
+}
+
+// typedRegistrations finds the `ExampleResource{},` entries
+func typedRegistrations(body *ast.BlockStmt, dataSource bool) []Registration {
+	regs := []Registration{}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		if ident, ok := cl.Type.(*ast.Ident); ok {
+			regs = append(regs, Registration{
+				Target:     ident.Name,
+				Typed:      true,
+				DataSource: dataSource,
+			})
+		}
+
+		return true
+	})
+
+	return regs
+}
+
+func stringLiteral(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return s, true
+}
+
+// nameFromFileName is the fallback when an element can't be found in registration.go
+func nameFromFileName(fileName string) string {
+	name := strings.TrimSuffix(fileName, ".go")
+	name = strings.TrimSuffix(name, "_data_source")
+	name = strings.TrimSuffix(name, "_resource")
+	name = strings.TrimPrefix(name, "resource_arm_")
+	name = strings.TrimPrefix(name, "data_source_")
+
+	return "azurerm_" + name
+}
@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRegistrations(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		expected []Registration
+	}{
+		{
+			name: "untyped maps",
+			src: `package compute
+
+func (r Registration) SupportedDataSources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_virtual_machine": dataSourceVirtualMachine(),
+	}
+}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_availability_set": resourceAvailabilitySet(),
+		"azurerm_virtual_machine":  resourceVirtualMachine(),
+	}
+}
+`,
+			expected: []Registration{
+				{Name: "azurerm_virtual_machine", Target: "dataSourceVirtualMachine", DataSource: true},
+				{Name: "azurerm_availability_set", Target: "resourceAvailabilitySet"},
+				{Name: "azurerm_virtual_machine", Target: "resourceVirtualMachine"},
+			},
+		},
+		{
+			name: "untyped assignments",
+			src: `package compute
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	resources := map[string]*pluginsdk.Resource{
+		"azurerm_availability_set": resourceAvailabilitySet(),
+	}
+
+	if !features.FourPointOhBeta() {
+		resources["azurerm_virtual_machine"] = resourceVirtualMachine()
+	}
+
+	return resources
+}
+`,
+			expected: []Registration{
+				{Name: "azurerm_availability_set", Target: "resourceAvailabilitySet"},
+				{Name: "azurerm_virtual_machine", Target: "resourceVirtualMachine"},
+			},
+		},
+		{
+			name: "untyped entries that aren't a local function call are skipped",
+			src: `package compute
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	name := "azurerm_dynamic"
+	return map[string]*pluginsdk.Resource{
+		name:                       resourceDynamic(),
+		"azurerm_other_package":    other.Resource(),
+		"azurerm_variable":         existing,
+		"azurerm_virtual_machine":  resourceVirtualMachine(),
+	}
+}
+`,
+			expected: []Registration{
+				{Name: "azurerm_virtual_machine", Target: "resourceVirtualMachine"},
+			},
+		},
+		{
+			name: "typed slices",
+			src: `package compute
+
+func (r Registration) DataSources() []sdk.DataSource {
+	return []sdk.DataSource{
+		SshPublicKeyDataSource{},
+	}
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		SshPublicKeyResource{},
+		GalleryApplicationResource{},
+	}
+}
+`,
+			expected: []Registration{
+				{Target: "SshPublicKeyDataSource", Typed: true, DataSource: true},
+				{Target: "SshPublicKeyResource", Typed: true},
+				{Target: "GalleryApplicationResource", Typed: true},
+			},
+		},
+		{
+			name: "functions without a receiver are ignored",
+			src: `package compute
+
+func SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_virtual_machine": resourceVirtualMachine(),
+	}
+}
+
+func (r Registration) Name() string {
+	return "Compute"
+}
+`,
+			expected: []Registration{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseGoFile("registration.go", []byte(tc.src))
+			if err != nil {
+				t.Fatalf("parsing fixture: %v", err)
+			}
+
+			if got := parseRegistrations(f); !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFileDeclarations(t *testing.T) {
+	src := `package compute
+
+type SshPublicKeyResource struct{}
+
+type SshPublicKeyModel struct{}
+
+type GalleryApplicationResource struct{}
+
+func (r SshPublicKeyResource) ResourceType() string {
+	return "azurerm_ssh_public_key"
+}
+
+func (r *GalleryApplicationResource) ResourceType() string {
+	return "azurerm_gallery_application"
+}
+
+func (r SshPublicKeyResource) ModelObject() interface{} {
+	return &SshPublicKeyModel{}
+}
+
+func resourceVirtualMachine() *pluginsdk.Resource {
+	return nil
+}
+`
+
+	f, err := parseGoFile("thing_resource.go", []byte(src))
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+	d := fileDeclarations(f)
+
+	types := map[string]string{
+		"SshPublicKeyResource":       "azurerm_ssh_public_key",
+		"SshPublicKeyModel":          "",
+		"GalleryApplicationResource": "azurerm_gallery_application",
+	}
+	if !reflect.DeepEqual(d.types, types) {
+		t.Fatalf("expected types %v, got %v", types, d.types)
+	}
+
+	funcs := map[string]bool{"resourceVirtualMachine": true}
+	if !reflect.DeepEqual(d.funcs, funcs) {
+		t.Fatalf("expected funcs %v, got %v", funcs, d.funcs)
+	}
+}
+
+func TestNameFromFileName(t *testing.T) {
+	cases := map[string]string{
+		"virtual_machine_resource.go":     "azurerm_virtual_machine",
+		"virtual_machine_data_source.go":  "azurerm_virtual_machine",
+		"resource_arm_virtual_machine.go": "azurerm_virtual_machine",
+		"data_source_virtual_machine.go":  "azurerm_virtual_machine",
+		"ssh_public_key.go":               "azurerm_ssh_public_key",
+	}
+
+	for fileName, expected := range cases {
+		t.Run(fileName, func(t *testing.T) {
+			if got := nameFromFileName(fileName); got != expected {
+				t.Fatalf("expected %s, got %s", expected, got)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"regexp"
 	"sort"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -70,6 +73,50 @@ func (r Repo) CheckoutTag(tag string) error {
 	return nil
 }
 
+// TagFS returns a read only filesystem of the tag's tree, leaving the worktree and branches untouched
+func (r Repo) TagFS(tag string) (fs.FS, error) {
+	h, err := r.TagHash(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := r.Git.CommitObject(*h)
+	if err != nil {
+		return nil, fmt.Errorf("getting commit %s for %s: %w", h, tag, err)
+	}
+
+	return newTreeFS(commit)
+}
+
+// TagHash returns the commit a tag points to
+func (r Repo) TagHash(tag string) (*plumbing.Hash, error) {
+	// resolving the tag peels annotated tags down to the commit
+	h, err := r.Git.ResolveRevision(plumbing.Revision("refs/tags/" + tag))
+	if err != nil {
+		return nil, fmt.Errorf("resolving tag %s: %w", tag, err)
+	}
+
+	return h, nil
+}
+
+// tagDate is when an annotated tag was made, or when the commit a lightweight tag points to was
+func (r Repo) tagDate(ref *plumbing.Reference, commit plumbing.Hash) (time.Time, error) {
+	t, err := r.Git.TagObject(ref.Hash())
+	if err == nil {
+		return t.Tagger.When, nil
+	}
+	if !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return time.Time{}, fmt.Errorf("getting tag object %s: %w", ref.Hash(), err)
+	}
+
+	c, err := r.Git.CommitObject(commit)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting commit %s: %w", commit, err)
+	}
+
+	return c.Committer.When, nil
+}
+
 func (r Repo) GetVersions() (*[]Version, error) {
 	tags, err := r.Git.Tags()
 	if err != nil {
@@ -80,12 +127,14 @@ func (r Repo) GetVersions() (*[]Version, error) {
 	versionRegex := regexp.MustCompile(`v[0-9]+\.[0-9]+\.[0-9]+`)
 
 	versionTags := []string{}
+	refs := map[string]*plumbing.Reference{}
 	tagCount := 0
 	err = tags.ForEach(func(ref *plumbing.Reference) error {
 		v := ref.Name().Short()
 
 		if versionRegex.MatchString(v) {
 			versionTags = append(versionTags, v)
+			refs[v] = ref
 		}
 
 		tagCount++
@@ -105,8 +154,20 @@ func (r Repo) GetVersions() (*[]Version, error) {
 
 	versions := []Version{}
 	for _, v := range versionTags {
+		h, err := r.TagHash(v)
+		if err != nil {
+			return nil, err
+		}
+
+		date, err := r.tagDate(refs[v], *h)
+		if err != nil {
+			return nil, fmt.Errorf("getting date of %s: %w", v, err)
+		}
+
 		versions = append(versions, Version{
 			Name: v,
+			Hash: h.String(),
+			Date: date,
 			Path: r.Path,
 		})
 	}
@@ -2,7 +2,7 @@ package provider
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
 	"regexp"
 	"strings"
 )
@@ -16,86 +16,78 @@ type Resource struct {
 
 func (r Resource) GetTotal() Totals {
 	t := r.ResourceOrData.GetTotal()
-	t.Resources++
+	t.Inc(MetricResources, 1)
 
 	if r.SharedCreateUpdate {
-		t.CreateUpdate++
+		t.Inc(MetricCreateUpdate, 1)
 	}
 
 	return t
 }
 
+var (
+	resourceFileRegex = regexp.MustCompile("[a-z_]+_resource.go$")
+
+	// before v2.40 resources were named resource_arm_example.go
+	legacyResourceFileRegex = regexp.MustCompile("^resource_arm_[a-z0-9_]+.go$")
+)
+
+func isResourceFile(name string) bool {
+	if strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+
+	return resourceFileRegex.MatchString(name) || legacyResourceFileRegex.MatchString(name)
+}
+
 func (s *Service) ScanResources() error {
 	// find all services
-	files, err := os.ReadDir(s.Path)
+	files, err := fs.ReadDir(s.files, ".")
 	if err != nil {
 		return fmt.Errorf("reading %s: %w", s.Path, err)
 	}
 
-	resourceFileRegex := regexp.MustCompile("[a-z_]+_resource.go$")
-	// dataSourceFileRegex := regexp.MustCompile("[a-z_]+_data_source.go$")
-
 	for _, f := range files {
 		name := f.Name()
 
-		if !resourceFileRegex.MatchString(name) {
+		if !isResourceFile(name) {
 			continue
 		}
 
-		// these are not resource files, skip
-		skip := map[string]bool{
-			"bot_service_base_resource.go":           true,
-			"export_base_resource.go":                true,
-			"assignment_base_resource.go":            true,
-			"container_registry_migrate_resource.go": true,
-			"resource_group_data_source_resource.go": true,
-		}
-		if _, ok := skip[name]; ok {
+		if s.exclude(name) {
 			continue
 		}
 
-		// skip older migration rsource files
-		if strings.Contains(name, "migration_resource.go") ||
-			strings.Contains(name, "migration_resource_test.go") ||
-			strings.Contains(name, "migration_test_resource.go") {
-			continue
+		bytes, err := fs.ReadFile(s.files, name)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", f.Name(), err)
 		}
 
-		bytes, err := os.ReadFile(s.Path + "/" + f.Name())
+		file, err := parseGoFile(name, bytes)
 		if err != nil {
-			return fmt.Errorf("reading %s: %w", f.Name(), err)
+			s.addDiagnostic(name, DiagnosticParse, "%v", err)
+			continue
 		}
-		content := string(bytes)
 
 		r := Resource{
-			ResourceOrData: s.GetResourceOrDataFor(f, content),
+			ResourceOrData: s.GetResourceOrDataFor(f, bytes, file),
 		}
 
 		// Shared Created/Update (only for plugin-sdk??)
 		if !r.IsTyped {
-			createFunctionRegex := regexp.MustCompile("Create: *[a-zA-Z0-9]+,")
-			updateFunctionRegex := regexp.MustCompile("Update: *[a-zA-Z0-9]+,")
-
-			creates := createFunctionRegex.FindAllString(content, -1)
-			updates := updateFunctionRegex.FindAllString(content, -1)
-
-			// sanity checks
-			if len(creates) == 0 {
-				return fmt.Errorf("matching 'Create:' for %s", r.GoFileName)
-			}
-			if len(creates) > 1 {
-				return fmt.Errorf("found multiple 'Create:'s for %s: %s", r.GoFileName, strings.Join(creates, ", "))
-			}
-			if len(updates) > 1 {
-				return fmt.Errorf("found multiple 'Update:'s for %s: %s", r.GoFileName, strings.Join(updates, ", "))
-			}
-			if len(updates) == 1 {
-				createFunction := strings.Trim(strings.Split(creates[0], " ")[1], ",")
-				updateFunction := strings.Trim(strings.Split(updates[0], " ")[1], ",")
-
-				if createFunction == updateFunction {
-					r.SharedCreateUpdate = true
-				}
+			creates := keyedIdents(file, "Create")
+			updates := keyedIdents(file, "Update")
+
+			// sanity checks, the resource is still counted but shared create/update can't be determined
+			switch {
+			case len(creates) == 0:
+				s.addDiagnostic(name, DiagnosticCreateMissing, "no 'Create:' found")
+			case len(creates) > 1:
+				s.addDiagnostic(name, DiagnosticCreateMultiple, "found multiple 'Create:'s: %s", strings.Join(creates, ", "))
+			case len(updates) > 1:
+				s.addDiagnostic(name, DiagnosticUpdateMultiple, "found multiple 'Update:'s: %s", strings.Join(updates, ", "))
+			case len(updates) == 1 && creates[0] == updates[0]:
+				r.SharedCreateUpdate = true
 			}
 		}
 		s.Resources = append(s.Resources, r)
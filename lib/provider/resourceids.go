@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ResourceID is a resource id parse function from commonids or go-azure-sdk that can replace a built in parser
+type ResourceID struct {
+	Package string // import path, ie github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/virtualmachines
+	Func    string // ie ParseVirtualMachineID
+}
+
+func (id ResourceID) String() string {
+	return path.Base(id.Package) + "." + id.Func
+}
+
+// ResourceIDs are the parse functions available to the provider keyed by id name, ie VirtualMachine
+type ResourceIDs map[string][]ResourceID
+
+var (
+	// vendored packages with resource ids, go-azure-sdk has one id_*.go file per id
+	resourceIDPaths = []string{
+		"vendor/github.com/hashicorp/go-azure-helpers/resourcemanager/commonids",
+		"vendor/github.com/hashicorp/go-azure-sdk/resource-manager",
+	}
+
+	resourceIDParseFuncRegex = regexp.MustCompile(`(?m)^func Parse(\w+)ID\(`)
+)
+
+// ScanResourceIDs finds the commonids and go-azure-sdk resource id parsers vendored into the provider
+func (v *Version) ScanResourceIDs() (ResourceIDs, error) {
+	ids := ResourceIDs{}
+
+	for _, root := range resourceIDPaths {
+		err := fs.WalkDir(v.FS, root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// not every version vendors both
+				if p == root && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipDir
+				}
+				return err
+			}
+
+			if d.IsDir() || !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+				return nil
+			}
+
+			b, err := fs.ReadFile(v.FS, p)
+			if err != nil {
+				return fmt.Errorf("reading %s/%s: %w", v.Path, p, err)
+			}
+
+			for _, m := range resourceIDParseFuncRegex.FindAllStringSubmatch(string(b), -1) {
+				ids[m[1]] = append(ids[m[1]], ResourceID{
+					Package: strings.TrimPrefix(path.Dir(p), "vendor/"),
+					Func:    "Parse" + m[1] + "ID",
+				})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scanning resource ids in %s/%s: %w", v.Path, root, err)
+		}
+	}
+
+	// commonids first, then the newest api versions
+	for _, l := range ids {
+		sort.SliceStable(l, func(i, j int) bool {
+			ci, cj := strings.HasSuffix(l[i].Package, "/commonids"), strings.HasSuffix(l[j].Package, "/commonids")
+			if ci != cj {
+				return ci
+			}
+			return l[i].Package > l[j].Package
+		})
+	}
+
+	return ids, nil
+}
+
+// EquivalentsOf returns the resource ids that can replace a built in parser, ie VirtualMachineID or NewVirtualMachineID
+func (ids ResourceIDs) EquivalentsOf(parser string) []ResourceID {
+	return ids[builtInParserIDName(parser)]
+}
+
+// builtInParserIDName strips a parse package function down to the id name, ie VirtualMachineIDInsensitively -> VirtualMachine
+func builtInParserIDName(parser string) string {
+	name := strings.TrimPrefix(parser, "New")
+	name = strings.TrimSuffix(name, "Insensitively")
+	name = strings.TrimSuffix(name, "ID")
+	return name
+}
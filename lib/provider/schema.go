@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Schema is the terraform schema of a resource or data source as declared in the go source
+type Schema struct {
+	Attributes []Attribute  // flattened, nested blocks follow their parent
+	Model      []ModelField // typed only, the tfschema tagged fields of the model structs
+}
+
+type Attribute struct {
+	Name     string
+	Path     string // dotted path from the top level, ie os_disk.caching
+	Depth    int    // 0 for top level attributes
+	Type     string // String, Int, Bool, Float, List, Set, Map, empty when unknown
+	ElemType string // for lists/sets/maps of primitives, Block for nested blocks
+
+	Required  bool
+	Optional  bool
+	Computed  bool
+	ForceNew  bool
+	Sensitive bool
+
+	// the attribute comes from a function defined elsewhere (ie commonschema.Location()) so the above is unknown
+	Helper string
+}
+
+type ModelField struct {
+	Struct   string
+	Field    string
+	GoType   string
+	TfSchema string
+}
+
+// TopLevel returns the attributes at depth 0
+func (s Schema) TopLevel() []Attribute {
+	attrs := []Attribute{}
+	for _, a := range s.Attributes {
+		if a.Depth == 0 {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+func (s Schema) MaxDepth() int {
+	depth := 0
+	for _, a := range s.Attributes {
+		if a.Depth > depth {
+			depth = a.Depth
+		}
+	}
+	return depth
+}
+
+// Attribute returns the attribute at path
+func (s Schema) Attribute(path string) (Attribute, bool) {
+	for _, a := range s.Attributes {
+		if a.Path == path {
+			return a, true
+		}
+	}
+	return Attribute{}, false
+}
+
+// extractSchema finds the schema for an untyped (Schema: map[string]*pluginsdk.Schema) or typed (Arguments()/Attributes()) element
+func extractSchema(f *ast.File, typed bool) *Schema {
+	funcs := map[string]*ast.FuncDecl{}
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil {
+			funcs[fd.Name.Name] = fd
+		}
+	}
+
+	se := schemaExtractor{funcs: funcs}
+
+	if typed {
+		return se.typedSchema(f)
+	}
+	return se.untypedSchema(f)
+}
+
+type schemaExtractor struct {
+	funcs map[string]*ast.FuncDecl
+}
+
+func (se schemaExtractor) untypedSchema(f *ast.File) *Schema {
+	var schema *Schema
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if schema != nil {
+			return false
+		}
+
+		// &pluginsdk.Resource{Read: ..., Schema: ...}, nested blocks don't have a Read
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok || !isResourceType(cl.Type) {
+			return true
+		}
+
+		if compositeValue(cl, "Read") == nil {
+			return true
+		}
+
+		if m := se.resolveSchemaMap(compositeValue(cl, "Schema")); m != nil {
+			schema = &Schema{}
+			schema.Attributes = se.attributes(m, "", 0)
+		}
+
+		return true
+	})
+
+	return schema
+}
+
+func (se schemaExtractor) typedSchema(f *ast.File) *Schema {
+	var schema *Schema
+
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || fd.Body == nil {
+			continue
+		}
+
+		if fd.Name.Name != "Arguments" && fd.Name.Name != "Attributes" {
+			continue
+		}
+
+		if m := se.firstSchemaMap(fd.Body); m != nil {
+			if schema == nil {
+				schema = &Schema{}
+			}
+			schema.Attributes = append(schema.Attributes, se.attributes(m, "", 0)...)
+		}
+	}
+
+	model := modelFields(f)
+	if len(model) > 0 {
+		if schema == nil {
+			schema = &Schema{}
+		}
+		schema.Model = model
+	}
+
+	return schema
+}
+
+// resolveSchemaMap follows `Schema: someFunc()` to the map literal when the function is in the same file
+func (se schemaExtractor) resolveSchemaMap(e ast.Expr) *ast.CompositeLit {
+	switch e := e.(type) {
+	case *ast.CompositeLit:
+		if _, ok := e.Type.(*ast.MapType); ok {
+			return e
+		}
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok {
+			if fd, ok := se.funcs[ident.Name]; ok && fd.Body != nil {
+				return se.firstSchemaMap(fd.Body)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (se schemaExtractor) firstSchemaMap(body *ast.BlockStmt) *ast.CompositeLit {
+	var m *ast.CompositeLit
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if m != nil {
+			return false
+		}
+
+		if cl, ok := n.(*ast.CompositeLit); ok {
+			if mt, ok := cl.Type.(*ast.MapType); ok && isSchemaType(mt.Value) {
+				m = cl
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return m
+}
+
+func (se schemaExtractor) attributes(m *ast.CompositeLit, parent string, depth int) []Attribute {
+	attrs := []Attribute{}
+
+	for _, elt := range m.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		name, ok := stringLiteral(kv.Key)
+		if !ok {
+			// a constant, use its name
+			name = types.ExprString(kv.Key)
+		}
+
+		path := name
+		if parent != "" {
+			path = parent + "." + name
+		}
+
+		a := Attribute{
+			Name:  name,
+			Path:  path,
+			Depth: depth,
+		}
+
+		cl := se.resolveSchema(kv.Value)
+		if cl == nil {
+			a.Helper = types.ExprString(kv.Value)
+			attrs = append(attrs, a)
+			continue
+		}
+
+		a.Type = strings.TrimPrefix(selectorName(compositeValue(cl, "Type")), "Type")
+		a.Required = isTrue(compositeValue(cl, "Required"))
+		a.Optional = isTrue(compositeValue(cl, "Optional"))
+		a.Computed = isTrue(compositeValue(cl, "Computed"))
+		a.ForceNew = isTrue(compositeValue(cl, "ForceNew"))
+		a.Sensitive = isTrue(compositeValue(cl, "Sensitive"))
+
+		var nested []Attribute
+		if elem := unwrapCompositeLit(compositeValue(cl, "Elem")); elem != nil {
+			if isResourceType(elem.Type) {
+				a.ElemType = "Block"
+				if nm := se.resolveSchemaMap(compositeValue(elem, "Schema")); nm != nil {
+					nested = se.attributes(nm, path, depth+1)
+				}
+			} else {
+				a.ElemType = strings.TrimPrefix(selectorName(compositeValue(elem, "Type")), "Type")
+			}
+		}
+
+		attrs = append(attrs, a)
+		attrs = append(attrs, nested...)
+	}
+
+	return attrs
+}
+
+// resolveSchema returns the *pluginsdk.Schema literal for `{...}`, `&pluginsdk.Schema{...}` or a same file helper function
+func (se schemaExtractor) resolveSchema(e ast.Expr) *ast.CompositeLit {
+	if cl := unwrapCompositeLit(e); cl != nil {
+		return cl
+	}
+
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	fd, ok := se.funcs[ident.Name]
+	if !ok || fd.Body == nil {
+		return nil
+	}
+
+	for _, stmt := range fd.Body.List {
+		if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			if cl := unwrapCompositeLit(ret.Results[0]); cl != nil && (cl.Type == nil || isSchemaType(cl.Type)) {
+				return cl
+			}
+		}
+	}
+
+	return nil
+}
+
+func modelFields(f *ast.File) []ModelField {
+	fields := []ModelField{}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			for _, field := range st.Fields.List {
+				tag, ok := structTagValue(field.Tag, "tfschema")
+				if !ok {
+					continue
+				}
+
+				for _, name := range field.Names {
+					fields = append(fields, ModelField{
+						Struct:   ts.Name.Name,
+						Field:    name.Name,
+						GoType:   types.ExprString(field.Type),
+						TfSchema: strings.Split(tag, ",")[0],
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Struct < fields[j].Struct
+	})
+
+	return fields
+}
+
+// compositeValue returns the value for key in a keyed composite literal
+func compositeValue(cl *ast.CompositeLit, key string) ast.Expr {
+	for _, elt := range cl.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		if k, ok := kv.Key.(*ast.Ident); ok && k.Name == key {
+			return kv.Value
+		}
+	}
+
+	return nil
+}
+
+func unwrapCompositeLit(e ast.Expr) *ast.CompositeLit {
+	if u, ok := e.(*ast.UnaryExpr); ok {
+		e = u.X
+	}
+
+	cl, _ := e.(*ast.CompositeLit)
+	return cl
+}
+
+func selectorName(e ast.Expr) string {
+	if s, ok := e.(*ast.SelectorExpr); ok {
+		return s.Sel.Name
+	}
+	return ""
+}
+
+func isTrue(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+// isResourceType matches pluginsdk.Resource or schema.Resource
+func isResourceType(e ast.Expr) bool {
+	return selectorName(e) == "Resource"
+}
+
+// isSchemaType matches *pluginsdk.Schema or *schema.Schema
+func isSchemaType(e ast.Expr) bool {
+	if star, ok := e.(*ast.StarExpr); ok {
+		e = star.X
+	}
+	return selectorName(e) == "Schema"
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractSchema(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		typed    bool
+		expected *Schema
+	}{
+		{
+			name: "untyped",
+			src: `package compute
+
+func resourceAvailabilitySet() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read: resourceAvailabilitySetRead,
+
+		Schema: map[string]*pluginsdk.Schema{
+			"name": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"location": commonschema.Location(),
+
+			"zones": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				Elem: &pluginsdk.Schema{
+					Type: pluginsdk.TypeString,
+				},
+			},
+
+			"secret": &pluginsdk.Schema{
+				Type:      pluginsdk.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+`,
+			expected: &Schema{Attributes: []Attribute{
+				{Name: "name", Path: "name", Type: "String", Required: true, ForceNew: true},
+				{Name: "location", Path: "location", Helper: "commonschema.Location()"},
+				{Name: "zones", Path: "zones", Type: "List", ElemType: "String", Optional: true},
+				{Name: "secret", Path: "secret", Type: "String", Computed: true, Sensitive: true},
+			}},
+		},
+		{
+			name: "untyped nested blocks follow their parent",
+			src: `package compute
+
+func resourceVirtualMachine() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read: resourceVirtualMachineRead,
+
+		Schema: map[string]*pluginsdk.Schema{
+			"os_disk": {
+				Type:     pluginsdk.TypeList,
+				Required: true,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"caching": {
+							Type:     pluginsdk.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"name": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+`,
+			expected: &Schema{Attributes: []Attribute{
+				{Name: "os_disk", Path: "os_disk", Type: "List", ElemType: "Block", Required: true},
+				{Name: "caching", Path: "os_disk.caching", Depth: 1, Type: "String", Optional: true},
+				{Name: "name", Path: "name", Type: "String", Required: true},
+			}},
+		},
+		{
+			name: "untyped schema and attributes from same file functions",
+			src: `package compute
+
+func resourceAvailabilitySet() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read:   resourceAvailabilitySetRead,
+		Schema: resourceAvailabilitySetSchema(),
+	}
+}
+
+func resourceAvailabilitySetSchema() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name":  nameSchema(),
+		"other": otherSchema(),
+	}
+}
+
+func nameSchema() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeString,
+		Required: true,
+	}
+}
+`,
+			expected: &Schema{Attributes: []Attribute{
+				{Name: "name", Path: "name", Type: "String", Required: true},
+				{Name: "other", Path: "other", Helper: "otherSchema()"},
+			}},
+		},
+		{
+			name: "untyped constant keys use their name",
+			src: `package compute
+
+func resourceAvailabilitySet() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read: resourceAvailabilitySetRead,
+
+		Schema: map[string]*pluginsdk.Schema{
+			nameKey: {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+`,
+			expected: &Schema{Attributes: []Attribute{
+				{Name: "nameKey", Path: "nameKey", Type: "String", Required: true},
+			}},
+		},
+		{
+			name: "untyped without a read has no schema",
+			src: `package compute
+
+func nestedBlock() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"name": {
+				Type: pluginsdk.TypeString,
+			},
+		},
+	}
+}
+`,
+		},
+		{
+			name:  "typed arguments, attributes and model",
+			typed: true,
+			src: `package compute
+
+type SshPublicKeyModel struct {
+	Name      string ` + "`tfschema:\"name\"`" + `
+	PublicKey string ` + "`tfschema:\"public_key,removedInNextMajorVersion\"`" + `
+	internal  string
+}
+
+func (r SshPublicKeyResource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:     pluginsdk.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+	}
+}
+
+func (r SshPublicKeyResource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"public_key": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+	}
+}
+`,
+			expected: &Schema{
+				Attributes: []Attribute{
+					{Name: "name", Path: "name", Type: "String", Required: true, ForceNew: true},
+					{Name: "public_key", Path: "public_key", Type: "String", Computed: true},
+				},
+				Model: []ModelField{
+					{Struct: "SshPublicKeyModel", Field: "Name", GoType: "string", TfSchema: "name"},
+					{Struct: "SshPublicKeyModel", Field: "PublicKey", GoType: "string", TfSchema: "public_key"},
+				},
+			},
+		},
+		{
+			name:  "typed without schema functions or a model",
+			typed: true,
+			src: `package compute
+
+type SshPublicKeyResource struct{}
+
+func (r SshPublicKeyResource) ResourceType() string {
+	return "azurerm_ssh_public_key"
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseGoFile("thing_resource.go", []byte(tc.src))
+			if err != nil {
+				t.Fatalf("parsing fixture: %v", err)
+			}
+
+			if got := extractSchema(f, tc.typed); !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
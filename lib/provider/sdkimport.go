@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SdkImport is an import of a versioned azure api package, ie
+// github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/virtualmachines or
+// github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute
+type SdkImport struct {
+	Sdk        string // pandora or track1
+	Path       string
+	Service    string // azure service, ie compute
+	ApiVersion string // ie 2022-03-01 or 2022-03-01-preview
+	Package    string // resource package, ie virtualmachines, empty for the api version's meta client
+}
+
+var (
+	apiVersionRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(-[a-z0-9.]+)?$`)
+
+	// services/[preview/]<service>/mgmt/<api version>/<package>
+	track1ImportRegex = regexp.MustCompile(`^services/(?:preview/)?([^/]+)/mgmt/([^/]+)/(.+)$`)
+)
+
+// parseSdkImport returns the service and api version for versioned sdk import paths
+func parseSdkImport(path string) (SdkImport, bool) {
+	if strings.HasPrefix(path, importPathAzureSdkGo+"services/") {
+		m := track1ImportRegex.FindStringSubmatch(strings.TrimPrefix(path, importPathAzureSdkGo))
+		if m == nil || !apiVersionRegex.MatchString(m[2]) {
+			return SdkImport{}, false
+		}
+
+		return SdkImport{
+			Sdk:        SdkTrack1,
+			Path:       path,
+			Service:    m[1],
+			ApiVersion: m[2],
+			Package:    m[3],
+		}, true
+	}
+
+	if !strings.HasPrefix(path, importPathPandora+"resource-manager/") {
+		return SdkImport{}, false
+	}
+
+	// <service>/<api version>[/<package>]
+	parts := strings.Split(strings.TrimPrefix(path, importPathPandora+"resource-manager/"), "/")
+	if len(parts) < 2 || !apiVersionRegex.MatchString(parts[1]) {
+		return SdkImport{}, false
+	}
+
+	i := SdkImport{
+		Sdk:        SdkPandora,
+		Path:       path,
+		Service:    parts[0],
+		ApiVersion: parts[1],
+	}
+
+	if len(parts) > 2 {
+		i.Package = strings.Join(parts[2:], "/")
+	}
+
+	return i, true
+}
+
+// ApiDate is the date the api version was released
+func (i SdkImport) ApiDate() time.Time {
+	// already matched apiVersionRegex so this can't fail
+	t, _ := time.Parse("2006-01-02", i.ApiVersion[:10])
+	return t
+}
+
+// Age is how old the api version was at a point in time, ie the date of the provider version
+func (i SdkImport) Age(at time.Time) time.Duration {
+	return at.Sub(i.ApiDate())
+}
+
+// OldestSdkImport returns the import with the oldest api version
+func (rds ResourceOrData) OldestSdkImport() (SdkImport, bool) {
+	if len(rds.SdkImports) == 0 {
+		return SdkImport{}, false
+	}
+
+	oldest := rds.SdkImports[0]
+	for _, i := range rds.SdkImports[1:] {
+		if i.ApiDate().Before(oldest.ApiDate()) {
+			oldest = i
+		}
+	}
+
+	return oldest, true
+}
+
+func sdkImportsOf(imports map[string]string) []SdkImport {
+	sdkImports := []SdkImport{}
+	for path := range imports {
+		if i, ok := parseSdkImport(path); ok {
+			sdkImports = append(sdkImports, i)
+		}
+	}
+
+	// imports are a map so order them for stable output
+	sort.Slice(sdkImports, func(i, j int) bool {
+		return sdkImports[i].Path < sdkImports[j].Path
+	})
+
+	return sdkImports
+}
+
+// ApiVersions returns the azure services the service's resources and data sources use, keyed by
+// azure service then api version with the names of the elements using it
+func (s *Service) ApiVersions() map[string]map[string][]string {
+	versions := map[string]map[string][]string{}
+
+	for _, e := range s.FilterResourcesDatas(func(rds ResourceOrData) bool { return len(rds.SdkImports) > 0 }) {
+		seen := map[string]bool{}
+
+		for _, i := range e.SdkImports {
+			key := i.Service + "/" + i.ApiVersion
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if versions[i.Service] == nil {
+				versions[i.Service] = map[string][]string{}
+			}
+			versions[i.Service][i.ApiVersion] = append(versions[i.Service][i.ApiVersion], e.Name)
+		}
+	}
+
+	return versions
+}
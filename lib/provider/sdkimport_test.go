@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSdkImport(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected *SdkImport
+	}{
+		{
+			path:     "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute",
+			expected: &SdkImport{Sdk: SdkTrack1, Service: "compute", ApiVersion: "2021-11-01", Package: "compute"},
+		},
+		{
+			path: "github.com/Azure/azure-sdk-for-go/services/preview/security/mgmt/v3.0/security",
+		},
+		{
+			path:     "github.com/Azure/azure-sdk-for-go/services/preview/monitor/mgmt/2021-07-01-preview/insights",
+			expected: &SdkImport{Sdk: SdkTrack1, Service: "monitor", ApiVersion: "2021-07-01-preview", Package: "insights"},
+		},
+		{
+			path:     "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage",
+			expected: &SdkImport{Sdk: SdkTrack1, Service: "storage", ApiVersion: "2021-09-01", Package: "storage"},
+		},
+		{
+			path: "github.com/Azure/azure-sdk-for-go/services/compute",
+		},
+		{
+			path: "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute",
+		},
+		{
+			path:     "github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/virtualmachines",
+			expected: &SdkImport{Sdk: SdkPandora, Service: "compute", ApiVersion: "2022-03-01", Package: "virtualmachines"},
+		},
+		{
+			path:     "github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01",
+			expected: &SdkImport{Sdk: SdkPandora, Service: "compute", ApiVersion: "2022-03-01"},
+		},
+		{
+			path:     "github.com/hashicorp/go-azure-sdk/resource-manager/containerservice/2023-04-02-preview/managedclusters",
+			expected: &SdkImport{Sdk: SdkPandora, Service: "containerservice", ApiVersion: "2023-04-02-preview", Package: "managedclusters"},
+		},
+		{
+			path:     "github.com/hashicorp/go-azure-sdk/resource-manager/web/2016-06-01/connections/sub",
+			expected: &SdkImport{Sdk: SdkPandora, Service: "web", ApiVersion: "2016-06-01", Package: "connections/sub"},
+		},
+		{
+			path: "github.com/hashicorp/go-azure-sdk/resource-manager/compute",
+		},
+		{
+			path: "github.com/hashicorp/go-azure-sdk/resource-manager/compute/latest/virtualmachines",
+		},
+		{
+			path: "github.com/hashicorp/go-azure-sdk/sdk/client",
+		},
+		{
+			path: "github.com/tombuildsstuff/kermit/sdk/compute/2022-08-01/compute",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			got, ok := parseSdkImport(tc.path)
+
+			if tc.expected == nil {
+				if ok {
+					t.Fatalf("expected no match, got %+v", got)
+				}
+				return
+			}
+
+			expected := *tc.expected
+			expected.Path = tc.path
+			if !ok || got != expected {
+				t.Fatalf("expected %+v, got %+v", expected, got)
+			}
+		})
+	}
+}
+
+func TestOldestSdkImport(t *testing.T) {
+	rds := ResourceOrData{SdkImports: []SdkImport{
+		{ApiVersion: "2022-03-01"},
+		{ApiVersion: "2019-12-01-preview"},
+		{ApiVersion: "2021-11-01"},
+	}}
+
+	oldest, ok := rds.OldestSdkImport()
+	if !ok || oldest.ApiVersion != "2019-12-01-preview" {
+		t.Fatalf("expected 2019-12-01-preview, got %s", oldest.ApiVersion)
+	}
+
+	at := time.Date(2019, 12, 11, 0, 0, 0, 0, time.UTC)
+	if age := oldest.Age(at); age != 10*24*time.Hour {
+		t.Fatalf("expected 10 days, got %s", age)
+	}
+
+	if _, ok := (ResourceOrData{}).OldestSdkImport(); ok {
+		t.Fatalf("expected no oldest import without imports")
+	}
+}
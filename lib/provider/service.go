@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"fmt"
+	"io/fs"
 	"sort"
 )
 
@@ -8,10 +10,45 @@ type Service struct {
 	Name string
 	Path string // should this just be a *Repo?
 
+	files fs.FS // rooted at the service's folder
+
 	Resources   []Resource
 	DataSources []DataSource
 
-	// Clients []Client
+	RegistrationGoFileName string // empty if the service has no registration.go
+	Registrations          []Registration
+
+	Clients []Client // fields of client/client.go's Client struct
+
+	Diagnostics []Diagnostic // files that could not be fully scanned
+
+	Excluded []ExcludedFile // resource and data source files skipped by an exclusion
+
+	version     string // name of the provider version, exclusions can depend on it
+	scanSchemas bool
+	detectors   Detectors
+	exclusions  Exclusions
+}
+
+// Scan finds the service's resources and data sources and everything about them
+func (s *Service) Scan() error {
+	if err := s.ScanResources(); err != nil {
+		return fmt.Errorf("scanning resources for %s: %w", s.Name, err)
+	}
+	if err := s.ScanDataSources(); err != nil {
+		return fmt.Errorf("scanning data sources for %s: %w", s.Name, err)
+	}
+	if err := s.ScanRegistrations(); err != nil {
+		return fmt.Errorf("scanning registrations for %s: %w", s.Name, err)
+	}
+	if err := s.ScanTests(); err != nil {
+		return fmt.Errorf("scanning tests for %s: %w", s.Name, err)
+	}
+	if err := s.ScanClients(); err != nil {
+		return fmt.Errorf("scanning clients for %s: %w", s.Name, err)
+	}
+
+	return nil
 }
 
 func (s *Service) CountResourcesDataSources() int {
@@ -20,12 +57,12 @@ func (s *Service) CountResourcesDataSources() int {
 
 func (s *Service) CalculateTotals() Totals {
 	t := s.CalculateDataSourceTotals().Add(s.CalculateResourceTotals())
-	t.Services = 1
+	t[MetricServices] = 1
 	return t
 }
 
 func (s *Service) CalculateResourceTotals() Totals {
-	totals := Totals{Services: 1}
+	totals := Totals{MetricServices: 1}
 	for _, r := range s.Resources {
 		totals = totals.Add(r.GetTotal())
 	}
@@ -33,7 +70,7 @@ func (s *Service) CalculateResourceTotals() Totals {
 }
 
 func (s *Service) CalculateDataSourceTotals() Totals {
-	totals := Totals{Services: 1}
+	totals := Totals{MetricServices: 1}
 	for _, ds := range s.DataSources {
 		totals = totals.Add(ds.GetTotal())
 	}
@@ -44,13 +81,13 @@ func (s *Service) FilterResourcesDatasInterfaced(f func(rds interface{}) bool) [
 	rds := []ResourceOrData{}
 
 	for _, r := range s.Resources {
-		if f(r.ResourceOrData) {
+		if f(r) {
 			rds = append(rds, r.ResourceOrData)
 		}
 	}
 
 	for _, d := range s.DataSources {
-		if f(d.ResourceOrData) {
+		if f(d) {
 			rds = append(rds, d.ResourceOrData)
 		}
 	}
@@ -68,6 +105,12 @@ func (s *Service) FilterResourcesDatasInterfaced(f func(rds interface{}) bool) [
 
 func (s *Service) FilterResourcesDatas(f func(rds ResourceOrData) bool) []ResourceOrData {
 	return s.FilterResourcesDatasInterfaced(func(rds interface{}) bool {
-		return f(rds.(ResourceOrData))
+		switch rds := rds.(type) {
+		case Resource:
+			return f(rds.ResourceOrData)
+		case DataSource:
+			return f(rds.ResourceOrData)
+		}
+		return false
 	})
 }
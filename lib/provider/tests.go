@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"io/fs"
+	"strings"
+)
+
+// AccTests is the acceptance test inventory for a resource or data source
+type AccTests struct {
+	Names []string // TestAcc... functions across all of the element's test files
+
+	HasBasic          bool
+	HasRequiresImport bool
+	HasComplete       bool
+	HasUpdate         bool
+}
+
+func (t AccTests) Count() int {
+	return len(t.Names)
+}
+
+// MissingStandardResourceTests returns which of the standard test cases a resource is missing
+func (t AccTests) MissingStandardResourceTests() []string {
+	missing := t.MissingStandardDataSourceTests()
+
+	if !t.HasRequiresImport {
+		missing = append(missing, "requiresImport")
+	}
+	if !t.HasComplete {
+		missing = append(missing, "complete")
+	}
+	if !t.HasUpdate {
+		missing = append(missing, "update")
+	}
+
+	return missing
+}
+
+// MissingStandardDataSourceTests returns which of the standard test cases a data source is missing
+func (t AccTests) MissingStandardDataSourceTests() []string {
+	missing := []string{}
+
+	if !t.HasBasic {
+		missing = append(missing, "basic")
+	}
+
+	return missing
+}
+
+func (t *AccTests) add(name string) {
+	t.Names = append(t.Names, name)
+
+	// TestAccExample_basic
+	switch name[strings.LastIndex(name, "_")+1:] {
+	case "basic":
+		t.HasBasic = true
+	case "requiresImport":
+		t.HasRequiresImport = true
+	case "complete":
+		t.HasComplete = true
+	case "update":
+		t.HasUpdate = true
+	}
+}
+
+// ScanTests finds the test files for each resource and data source and builds their acceptance test inventory
+func (s *Service) ScanTests() error {
+	for i := range s.Resources {
+		if err := s.scanTestsFor(&s.Resources[i].ResourceOrData); err != nil {
+			return err
+		}
+	}
+
+	for i := range s.DataSources {
+		if err := s.scanTestsFor(&s.DataSources[i].ResourceOrData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) scanTestsFor(e *ResourceOrData) error {
+	testFileName := strings.TrimSuffix(e.GoFileName, ".go") + "_test.go"
+
+	// tests live next to the resource, or in a tests sub package in older versions
+	for _, path := range []string{testFileName, "tests/" + testFileName} {
+		bytes, err := fs.ReadFile(s.files, path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("reading %s/%s: %w", s.Path, path, err)
+		}
+
+		f, err := parseGoFile(testFileName, bytes)
+		if err != nil {
+			s.addDiagnostic(path, DiagnosticParse, "%v", err)
+			continue
+		}
+
+		e.TestPaths = append(e.TestPaths, s.Path+"/"+path)
+
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || !strings.HasPrefix(fd.Name.Name, "TestAcc") {
+				continue
+			}
+
+			e.AccTests.add(fd.Name.Name)
+		}
+	}
+
+	return nil
+}
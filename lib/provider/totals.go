@@ -1,67 +1,151 @@
 package provider
 
-type Totals struct {
-	Services     int
-	Resources    int
-	DataSources  int
-	SdkTrack1    int
-	SdkPandora   int
-	SdkKermit    int
-	SdkGiovanni  int
-	SdkBoth      int
-	Typed        int
-	CreateUpdate int
-	BuiltInParse int
+import (
+	"sort"
+	"strings"
+)
+
+// Totals are counters keyed by metric name, they are summed from resources and data sources up to services and
+// versions so any metric an element records is aggregated without changes here
+type Totals map[string]int
+
+const (
+	MetricServices     = "services"
+	MetricResources    = "resources"
+	MetricDataSources  = "data_sources"
+	MetricSdkTrack1    = "sdk_track1"
+	MetricSdkTrack2    = "sdk_track2"
+	MetricSdkPandora   = "sdk_pandora"
+	MetricSdkKermit    = "sdk_kermit"
+	MetricSdkGiovanni  = "sdk_giovanni"
+	MetricSdkBoth      = "sdk_both"   // pandora and a legacy sdk, the same as MigrationPartial
+	MetricSdkLegacy    = "sdk_legacy" // any of track1, track2 or kermit, once per element however many it uses
+	MetricTyped        = "typed"
+	MetricCreateUpdate = "shared_create_update"
+	MetricBuiltInParse = "built_in_parse" // imports a parse package, see ResourceOrData.UsesBuiltInParse
+	MetricTested       = "tested"         // has at least one acceptance test
+	MetricAccTests     = "acc_tests"
+
+	metricDetectedPrefix = "detected:"
+)
+
+// DetectedMetric is the metric a config detector's results are recorded under
+func DetectedMetric(detector string) string {
+	return metricDetectedPrefix + detector
 }
 
+// Add returns the sum of both totals, neither is modified
 func (t Totals) Add(t2 Totals) Totals {
-	t.Services += t2.Services
-	t.Resources += t2.Resources
-	t.DataSources += t2.DataSources
-	t.SdkTrack1 += t2.SdkTrack1
-	t.SdkPandora += t2.SdkPandora
-	t.SdkKermit += t2.SdkKermit
-	t.SdkGiovanni += t2.SdkGiovanni
-	t.SdkBoth += t2.SdkBoth
-	t.Typed += t2.Typed
-	t.BuiltInParse += t2.BuiltInParse
-	t.CreateUpdate += t2.CreateUpdate
-	return t
+	sum := make(Totals, len(t)+len(t2))
+	for k, n := range t {
+		sum[k] += n
+	}
+	for k, n := range t2 {
+		sum[k] += n
+	}
+	return sum
 }
 
+// Inc adds n to a metric
+func (t Totals) Inc(metric string, n int) {
+	if n != 0 {
+		t[metric] += n
+	}
+}
+
+// Metrics returns the names of all recorded metrics sorted
+func (t Totals) Metrics() []string {
+	metrics := make([]string, 0, len(t))
+	for k := range t {
+		metrics = append(metrics, k)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// DetectedMetrics returns the detector names with results
+func (t Totals) DetectedMetrics() []string {
+	detectors := []string{}
+	for _, k := range t.Metrics() {
+		if strings.HasPrefix(k, metricDetectedPrefix) {
+			detectors = append(detectors, strings.TrimPrefix(k, metricDetectedPrefix))
+		}
+	}
+	return detectors
+}
+
+func (t Totals) Services() int     { return t[MetricServices] }
+func (t Totals) Resources() int    { return t[MetricResources] }
+func (t Totals) DataSources() int  { return t[MetricDataSources] }
+func (t Totals) SdkTrack1() int    { return t[MetricSdkTrack1] }
+func (t Totals) SdkTrack2() int    { return t[MetricSdkTrack2] }
+func (t Totals) SdkPandora() int   { return t[MetricSdkPandora] }
+func (t Totals) SdkKermit() int    { return t[MetricSdkKermit] }
+func (t Totals) SdkGiovanni() int  { return t[MetricSdkGiovanni] }
+func (t Totals) SdkBoth() int      { return t[MetricSdkBoth] }
+func (t Totals) SdkLegacy() int    { return t[MetricSdkLegacy] }
+func (t Totals) Typed() int        { return t[MetricTyped] }
+func (t Totals) CreateUpdate() int { return t[MetricCreateUpdate] }
+
+// BuiltInParse counts elements importing a parse package, before files were parsed it counted those calling
+// parse.<Func> so it now includes ones that only use the package's types
+func (t Totals) BuiltInParse() int { return t[MetricBuiltInParse] }
+
+func (t Totals) Tested() int   { return t[MetricTested] }
+func (t Totals) AccTests() int { return t[MetricAccTests] }
+
+// Detected is the sum of a config detector's results, the number of elements matched unless it counts
+func (t Totals) Detected(detector string) int { return t[DetectedMetric(detector)] }
+
 func (rds ResourceOrData) GetTotal() Totals {
 	t := Totals{}
 
 	if rds.IsTyped {
-		t.Typed++
+		t.Inc(MetricTyped, 1)
 	}
 
 	if rds.SdkAzureSdkGo {
-		t.SdkTrack1++
+		t.Inc(MetricSdkTrack1, 1)
+	}
+
+	if rds.SdkAzureSdkGoTrack2 {
+		t.Inc(MetricSdkTrack2, 1)
 	}
 
 	if rds.SdkPandora {
-		t.SdkPandora++
+		t.Inc(MetricSdkPandora, 1)
 	}
 
 	if rds.SdkKermit {
-		t.SdkKermit++
+		t.Inc(MetricSdkKermit, 1)
 	}
 
 	if rds.SdkGiovanni {
-		t.SdkGiovanni++
+		t.Inc(MetricSdkGiovanni, 1)
 	}
 
-	if rds.SdkKermit && rds.SdkAzureSdkGo {
-		t.SdkTrack1++
+	if rds.UsesLegacySdk() {
+		t.Inc(MetricSdkLegacy, 1)
 	}
 
-	if rds.SdkPandora && (rds.SdkAzureSdkGo || rds.SdkKermit) {
-		t.SdkBoth++
+	state := rds.MigrationState()
+	t.Inc(state.Metric(), 1)
+
+	if state == MigrationPartial {
+		t.Inc(MetricSdkBoth, 1)
 	}
 
 	if rds.UsesBuiltInParse {
-		t.BuiltInParse++
+		t.Inc(MetricBuiltInParse, 1)
+	}
+
+	if n := rds.AccTests.Count(); n > 0 {
+		t.Inc(MetricTested, 1)
+		t.Inc(MetricAccTests, n)
+	}
+
+	for name, n := range rds.Detected {
+		t.Inc(DetectedMetric(name), n)
 	}
 
 	return t
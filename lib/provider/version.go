@@ -1,63 +1,212 @@
 package provider
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"regexp"
+	"runtime"
+	"sync"
 	"time"
 )
 
 type Version struct {
 	Name string
+	Hash string // commit the tag points to
 	Date time.Time
 	Path string
+	FS   fs.FS `json:"-"` // where to read the source from, defaults to Path on disk
 
 	Services []Service
+
+	ScanSchemas bool       // schemas are large so only extract them when needed
+	Detectors   Detectors  `json:"-"` // user defined rules, must be compiled
+	Workers     int        `json:"-"` // services scanned at once, defaults to the number of cpus
+	Exclusions  Exclusions `json:"-"` // files to skip, must be compiled. nil uses the defaults
+
+	ServicesPath string `json:"-"` // folder containing the service packages, found automatically when empty
 }
 
-func (v *Version) ScanServices() error {
-	path := v.Path + "/internal/services"
+var (
+	// where the service packages have lived, newest first. they moved out of azurerm/ in v3.0.0
+	servicesPaths = []string{"internal/services", "azurerm/internal/services"}
 
-	// service folder location changed in v3.1.0
-	oldServicesPathRegex := regexp.MustCompile("v2.[123456]")
-	oldServicesPathMap := map[string]bool{"v2.71.0": true, "v2.70.0": true} // todo get this into the regex pattern
-	if _, ok := oldServicesPathMap[v.Name]; ok || oldServicesPathRegex.MatchString(v.Name) {
-		path = v.Path + "/azurerm/internal/services"
+	// before service packages (v2.10.0) every resource lived in the azurerm package
+	flatServicePath = "azurerm"
+)
+
+// FlatServiceName is the name of the pseudo service holding the resources and data sources of the azurerm package,
+// it is not a real service package
+const FlatServiceName = "azurerm"
+
+// findServicesPath probes the tree for the services folder, returns empty if there isn't one
+func (v *Version) findServicesPath() string {
+	for _, p := range servicesPaths {
+		if info, err := fs.Stat(v.FS, p); err == nil && info.IsDir() {
+			return p
+		}
 	}
 
-	// find all services
-	folders, err := os.ReadDir(path)
+	return ""
+}
+
+// hasFlatService checks if resources or data sources still live in the azurerm package, during the move
+// to service packages some were in both
+func (v *Version) hasFlatService() (bool, error) {
+	files, err := fs.ReadDir(v.FS, flatServicePath)
 	if err != nil {
-		return fmt.Errorf("reading %s: %w", path, err)
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading %s/%s: %w", v.Path, flatServicePath, err)
 	}
 
-	for _, f := range folders {
-		s := Service{
-			Name: f.Name(),
-			Path: path + "/" + f.Name(),
+	for _, f := range files {
+		if isResourceFile(f.Name()) || isDataSourceFile(f.Name()) {
+			return true, nil
 		}
+	}
+
+	return false, nil
+}
+
+func (v *Version) newService(name, path string) (Service, error) {
+	files, err := fs.Sub(v.FS, path)
+	if err != nil {
+		return Service{}, fmt.Errorf("opening %s/%s: %w", v.Path, path, err)
+	}
+
+	return Service{
+		Name:        name,
+		Path:        v.Path + "/" + path,
+		files:       files,
+		version:     v.Name,
+		scanSchemas: v.ScanSchemas,
+		detectors:   v.Detectors,
+		exclusions:  v.exclusions(),
+	}, nil
+}
+
+func (v *Version) exclusions() Exclusions {
+	if v.Exclusions == nil {
+		return DefaultExclusions
+	}
+	return v.Exclusions
+}
+
+// RulesFingerprint identifies the detectors and exclusions a version is scanned with
+func (v *Version) RulesFingerprint() string {
+	d, e := v.Detectors.Fingerprint(), v.exclusions().Fingerprint()
+	if d == "" && e == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(d+"\n"+e)))
+}
+
+func (v *Version) ScanServices() error {
+	if v.FS == nil {
+		v.FS = os.DirFS(v.Path)
+	}
+
+	path := v.ServicesPath
+	if path == "" {
+		path = v.findServicesPath()
+	}
 
-		// scan
-		err = s.ScanResources()
+	// find all services
+	services := []Service{}
+	if path != "" {
+		folders, err := fs.ReadDir(v.FS, path)
 		if err != nil {
-			return fmt.Errorf("scanning resources for %s: %w", s.Name, err)
+			return fmt.Errorf("reading %s/%s: %w", v.Path, path, err)
+		}
+
+		for _, f := range folders {
+			if !f.IsDir() {
+				continue
+			}
+
+			s, err := v.newService(f.Name(), path+"/"+f.Name())
+			if err != nil {
+				return err
+			}
+			services = append(services, s)
 		}
-		err = s.ScanDataSources()
+	}
+
+	// the flat package is scanned as a single service
+	if v.ServicesPath == "" {
+		flat, err := v.hasFlatService()
 		if err != nil {
-			return fmt.Errorf("scanning data sources for %s: %w", s.Name, err)
+			return err
+		}
+
+		if flat {
+			s, err := v.newService(FlatServiceName, flatServicePath)
+			if err != nil {
+				return err
+			}
+			services = append(services, s)
 		}
+	}
+
+	if len(services) == 0 {
+		return fmt.Errorf("no services found in %s, set the services path if the layout has changed", v.Path)
+	}
 
-		/*s.ScanClients()
+	// services are independent so scan them in parallel, results are kept in folder order
+	workers := v.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	errs := make([]error, len(services))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = services[i].Scan()
+			}
+		}()
+	}
+
+	for i := range services {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// the first failure in folder order so the error is the same every run
+	for _, err := range errs {
 		if err != nil {
-			return fmt.Errorf("scanning clients for %s: %w", s.Name, err)
+			return err
 		}
-		*/
-		v.Services = append(v.Services, s)
 	}
 
+	v.Services = append(v.Services, services...)
+
+	v.LinkServices()
+
 	return nil
 }
 
+// LinkServices points every resource and data source back at its service, ie after being unmarshalled
+func (v *Version) LinkServices() {
+	for i := range v.Services {
+		s := &v.Services[i]
+		for j := range s.Resources {
+			s.Resources[j].Service = s
+		}
+		for j := range s.DataSources {
+			s.DataSources[j].Service = s
+		}
+	}
+}
+
 func (v *Version) CalculateTotals() Totals {
 	totals := Totals{}
 	for _, s := range v.Services {
@@ -0,0 +1,175 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/katbyte/gogo-azurerm-info/lib/provider"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Format is stored with every version, bump it when the scanner changes what it records so old results are rescanned
+const Format = 14
+
+// Store persists scanned versions in a sqlite database keyed by tag, commit hash and the detectors and exclusions used
+type Store struct {
+	Path string
+	db   *sql.DB
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS versions (
+	tag        TEXT    NOT NULL,
+	hash       TEXT    NOT NULL,
+	format     INTEGER NOT NULL,
+	date       TIMESTAMP,
+	scanned_at TIMESTAMP NOT NULL,
+	totals     TEXT    NOT NULL,
+	detectors  TEXT    NOT NULL DEFAULT '', -- fingerprint of the detectors and exclusions
+	data       BLOB    NOT NULL,
+	PRIMARY KEY (tag, hash, detectors)
+);
+`
+
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+
+	// databases from before detectors were keyed on only hold old formats, so start them over
+	if _, err := db.Exec(`SELECT detectors FROM versions LIMIT 0`); err != nil {
+		if _, err := db.Exec(`DROP TABLE IF EXISTS versions`); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("dropping old versions table in %s: %w", path, err)
+		}
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating tables in %s: %w", path, err)
+	}
+
+	return &Store{
+		Path: path,
+		db:   db,
+	}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// GetVersion returns the stored scan for tag at hash, or nil if it hasn't been scanned with the current Format and rules,
+// see Version.RulesFingerprint. the date is the stored date column, which is what Totals reads
+func (s *Store) GetVersion(tag, hash, rules string) (*provider.Version, error) {
+	var data []byte
+	var date sql.NullTime
+	err := s.db.QueryRow(`SELECT data, date FROM versions WHERE tag = ? AND hash = ? AND detectors = ? AND format = ?`, tag, hash, rules, Format).Scan(&data, &date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying version %s (%s): %w", tag, hash, err)
+	}
+
+	v := provider.Version{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("unmarshalling version %s (%s): %w", tag, hash, err)
+	}
+	v.LinkServices()
+	v.Date = date.Time
+
+	return &v, nil
+}
+
+// VersionTotals is the totals of a stored version, without reading the whole scan
+type VersionTotals struct {
+	Name   string
+	Date   time.Time
+	Totals provider.Totals
+}
+
+// Totals returns the totals of every version stored with the current Format and rules, oldest first
+func (s *Store) Totals(rules string) ([]VersionTotals, error) {
+	rows, err := s.db.Query(`SELECT tag, date, totals FROM versions WHERE detectors = ? AND format = ?`, rules, Format)
+	if err != nil {
+		return nil, fmt.Errorf("querying totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []VersionTotals{}
+	for rows.Next() {
+		vt := VersionTotals{}
+		var date sql.NullTime
+		var data string
+		if err := rows.Scan(&vt.Name, &date, &data); err != nil {
+			return nil, fmt.Errorf("reading totals: %w", err)
+		}
+		vt.Date = date.Time
+
+		if err := json.Unmarshal([]byte(data), &vt.Totals); err != nil {
+			return nil, fmt.Errorf("unmarshalling totals for %s: %w", vt.Name, err)
+		}
+		totals = append(totals, vt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading totals: %w", err)
+	}
+
+	// sorted here as sqlite compares the stored dates as text, which breaks with mixed time zones. releases can
+	// share a date so the version decides those
+	sort.Slice(totals, func(i, j int) bool {
+		if !totals[i].Date.Equal(totals[j].Date) {
+			return totals[i].Date.Before(totals[j].Date)
+		}
+
+		vi, _ := version.NewVersion(totals[i].Name)
+		vj, _ := version.NewVersion(totals[j].Name)
+		return vi != nil && vj != nil && vi.LessThan(vj)
+	})
+
+	return totals, nil
+}
+
+// SetDate updates the date of a stored version, dates come from the tag rather then the scan so they can change
+// without the version needing to be rescanned
+func (s *Store) SetDate(tag, hash, rules string, date time.Time) error {
+	_, err := s.db.Exec(`UPDATE versions SET date = ? WHERE tag = ? AND hash = ? AND detectors = ? AND format = ?`, date, tag, hash, rules, Format)
+	if err != nil {
+		return fmt.Errorf("updating date of version %s (%s): %w", tag, hash, err)
+	}
+
+	return nil
+}
+
+// PutVersion stores a scanned version, replacing any previous scan of the same tag, hash and rules
+func (s *Store) PutVersion(v provider.Version) error {
+	if v.Hash == "" {
+		return fmt.Errorf("version %s has no commit hash", v.Name)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshalling version %s: %w", v.Name, err)
+	}
+
+	totals, err := json.Marshal(v.CalculateTotals())
+	if err != nil {
+		return fmt.Errorf("marshalling totals for %s: %w", v.Name, err)
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO versions (tag, hash, detectors, format, date, scanned_at, totals, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		v.Name, v.Hash, v.RulesFingerprint(), Format, v.Date, time.Now(), string(totals), data)
+	if err != nil {
+		return fmt.Errorf("inserting version %s (%s): %w", v.Name, v.Hash, err)
+	}
+
+	return nil
+}